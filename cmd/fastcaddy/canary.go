@@ -0,0 +1,131 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/youfun/fastcaddy/internal/utils"
+)
+
+var (
+	// canary 命令参数
+	canaryID      string
+	canaryTo      string
+	canaryPercent int
+)
+
+// canaryCmd 金丝雀发布命令
+var canaryCmd = &cobra.Command{
+	Use:   "canary",
+	Short: "金丝雀发布和蓝绿切换",
+	Long: `通过加权轮询在稳定版本和新版本上游之间按比例分配流量，
+并支持一次性全量切换 (promote) 或回滚 (abort)。
+开始发布时保存原有的负载均衡策略，promote 或 abort 后恢复。
+金丝雀上游的协议 (http/https/h2c) 必须与稳定版本上游一致。
+
+需要 Caddy 2.8 及以上版本（weighted_round_robin 选择策略）。
+
+示例:
+  fastcaddy canary set --id api.example.com --to localhost:9001 --percent 10
+  fastcaddy canary set --id api.example.com --to https://v2.internal:8443 --percent 10
+  fastcaddy canary set --id api.example.com --percent 50
+  fastcaddy canary status --id api.example.com
+  fastcaddy canary promote --id api.example.com
+  fastcaddy canary abort --id api.example.com`,
+}
+
+// canarySetCmd 设置金丝雀流量子命令
+var canarySetCmd = &cobra.Command{
+	Use:   "set",
+	Short: "设置金丝雀上游和流量比例",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		upstreams := utils.SplitList(canaryTo)
+		for _, upstream := range upstreams {
+			if !utils.ValidateURL(upstream) {
+				return fmt.Errorf("无效的上游地址: %s", upstream)
+			}
+		}
+
+		fc := newFastCaddy()
+
+		fmt.Printf("正在将 %s 的 %d%% 流量切换到金丝雀版本...\n", canaryID, canaryPercent)
+		if err := fc.Canary(canaryID, upstreams, canaryPercent); err != nil {
+			return fmt.Errorf("设置金丝雀发布失败: %w", err)
+		}
+
+		fmt.Printf("✓ 金丝雀发布已更新\n")
+		return nil
+	},
+}
+
+// canaryPromoteCmd 全量切换子命令
+var canaryPromoteCmd = &cobra.Command{
+	Use:   "promote",
+	Short: "将全部流量切换到金丝雀版本",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		fc := newFastCaddy()
+
+		fmt.Printf("正在全量切换 %s 到金丝雀版本...\n", canaryID)
+		if err := fc.PromoteCanary(canaryID); err != nil {
+			return fmt.Errorf("切换失败: %w", err)
+		}
+
+		fmt.Printf("✓ 已全量切换到新版本\n")
+		return nil
+	},
+}
+
+// canaryAbortCmd 回滚子命令
+var canaryAbortCmd = &cobra.Command{
+	Use:   "abort",
+	Short: "放弃金丝雀发布，流量回到稳定版本",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		fc := newFastCaddy()
+
+		fmt.Printf("正在回滚 %s 的金丝雀发布...\n", canaryID)
+		if err := fc.AbortCanary(canaryID); err != nil {
+			return fmt.Errorf("回滚失败: %w", err)
+		}
+
+		fmt.Printf("✓ 流量已回到稳定版本\n")
+		return nil
+	},
+}
+
+// canaryStatusCmd 状态子命令
+var canaryStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "查看金丝雀发布状态",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		fc := newFastCaddy()
+
+		status, err := fc.Routes.GetCanaryStatus(canaryID)
+		if err != nil {
+			return fmt.Errorf("获取金丝雀状态失败: %w", err)
+		}
+
+		fmt.Printf("路由: %s\n", canaryID)
+		fmt.Printf("稳定版本: %s (%d%%)\n", strings.Join(status.Stable, ", "), 100-status.Percent)
+		if len(status.Canary) == 0 {
+			fmt.Printf("金丝雀版本: 无\n")
+		} else {
+			fmt.Printf("金丝雀版本: %s (%d%%)\n", strings.Join(status.Canary, ", "), status.Percent)
+		}
+		return nil
+	},
+}
+
+func init() {
+	canaryCmd.PersistentFlags().StringVar(&canaryID, "id", "", "路由 ID（必需）")
+	canaryCmd.MarkPersistentFlagRequired("id")
+
+	canarySetCmd.Flags().StringVar(&canaryTo, "to", "", "金丝雀上游地址，用逗号分隔（为空时沿用当前金丝雀上游）")
+	canarySetCmd.Flags().IntVar(&canaryPercent, "percent", 10, "金丝雀流量百分比（1-99）")
+
+	canaryCmd.AddCommand(canarySetCmd)
+	canaryCmd.AddCommand(canaryPromoteCmd)
+	canaryCmd.AddCommand(canaryAbortCmd)
+	canaryCmd.AddCommand(canaryStatusCmd)
+	rootCmd.AddCommand(canaryCmd)
+}
@@ -0,0 +1,94 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/youfun/fastcaddy/internal/routes"
+	"github.com/youfun/fastcaddy/internal/utils"
+)
+
+var (
+	// cors 命令参数
+	corsID          string
+	corsOrigins     string
+	corsMethods     string
+	corsHeaders     string
+	corsExpose      string
+	corsCredentials bool
+	corsMaxAge      int
+)
+
+// corsCmd CORS 策略命令
+var corsCmd = &cobra.Command{
+	Use:   "cors",
+	Short: "管理路由的跨域 (CORS) 策略",
+	Long: `为已存在的路由设置跨域资源共享策略：自动处理 OPTIONS 预检请求，
+并为允许的来源添加 Access-Control-* 响应头。
+
+示例:
+  fastcaddy cors set --id api.example.com --origins https://app.example.com,https://admin.example.com
+  fastcaddy cors set --id api.example.com --origins https://app.example.com --credentials --max-age 3600
+  fastcaddy cors set --id public.example.com --origins '*' --methods GET,HEAD
+  fastcaddy cors remove --id api.example.com`,
+}
+
+// corsSetCmd 设置 CORS 策略子命令
+var corsSetCmd = &cobra.Command{
+	Use:   "set",
+	Short: "设置 CORS 策略",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		policy := routes.CORSPolicy{
+			Origins:       utils.SplitList(corsOrigins),
+			Methods:       utils.SplitList(corsMethods),
+			Headers:       utils.SplitList(corsHeaders),
+			ExposeHeaders: utils.SplitList(corsExpose),
+			Credentials:   corsCredentials,
+			MaxAge:        corsMaxAge,
+		}
+
+		fc := newFastCaddy()
+
+		fmt.Printf("正在为 %s 设置 CORS 策略...\n", corsID)
+		if err := fc.SetCORS(corsID, policy); err != nil {
+			return fmt.Errorf("设置 CORS 策略失败: %w", err)
+		}
+
+		fmt.Printf("✓ CORS 策略已设置\n")
+		return nil
+	},
+}
+
+// corsRemoveCmd 移除 CORS 策略子命令
+var corsRemoveCmd = &cobra.Command{
+	Use:   "remove",
+	Short: "移除 CORS 策略",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		fc := newFastCaddy()
+
+		fmt.Printf("正在移除 %s 的 CORS 策略...\n", corsID)
+		if err := fc.RemoveCORS(corsID); err != nil {
+			return fmt.Errorf("移除 CORS 策略失败: %w", err)
+		}
+
+		fmt.Printf("✓ CORS 策略已移除\n")
+		return nil
+	},
+}
+
+func init() {
+	corsCmd.PersistentFlags().StringVar(&corsID, "id", "", "路由 ID（必需）")
+	corsCmd.MarkPersistentFlagRequired("id")
+
+	corsSetCmd.Flags().StringVar(&corsOrigins, "origins", "", "允许的来源，用逗号分隔，* 表示任意来源（必需）")
+	corsSetCmd.Flags().StringVar(&corsMethods, "methods", "", "允许的请求方法，用逗号分隔（默认: GET,POST,PUT,PATCH,DELETE,OPTIONS）")
+	corsSetCmd.Flags().StringVar(&corsHeaders, "headers", "", "允许的请求头，用逗号分隔（默认: Content-Type,Authorization）")
+	corsSetCmd.Flags().StringVar(&corsExpose, "expose", "", "允许浏览器读取的响应头，用逗号分隔")
+	corsSetCmd.Flags().BoolVar(&corsCredentials, "credentials", false, "允许携带凭据（Cookie、Authorization），需要明确列出来源")
+	corsSetCmd.Flags().IntVar(&corsMaxAge, "max-age", 0, "预检结果缓存时间（秒）")
+	corsSetCmd.MarkFlagRequired("origins")
+
+	corsCmd.AddCommand(corsSetCmd)
+	corsCmd.AddCommand(corsRemoveCmd)
+	rootCmd.AddCommand(corsCmd)
+}
@@ -0,0 +1,170 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/youfun/fastcaddy/internal/routes"
+	"github.com/youfun/fastcaddy/internal/utils"
+)
+
+var (
+	// domain 命令参数
+	domainTo       string
+	domainCompress bool
+	domainStatus   int
+	domainBody     string
+	domainRemove   bool
+)
+
+// domainCmd 通配符域名命令
+var domainCmd = &cobra.Command{
+	Use:   "domain",
+	Short: "管理通配符域名下的子域名",
+	Long: `管理 *.<域名> 下的子域名反向代理。重复设置同一子域名会原地更新，不会产生重复路由。
+
+示例:
+  fastcaddy domain add example.com
+  fastcaddy domain set example.com api --to localhost:8080
+  fastcaddy domain set example.com web --to localhost:3000,localhost:3001 --compress
+  fastcaddy domain list example.com
+  fastcaddy domain remove example.com api
+  fastcaddy domain fallback example.com --status 404 --body "Unknown site"
+  fastcaddy domain fallback example.com --to localhost:9000
+  fastcaddy domain fallback example.com --remove`,
+}
+
+// domainAddCmd 添加通配符域名子命令
+var domainAddCmd = &cobra.Command{
+	Use:   "add <domain>",
+	Short: "添加通配符域名",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		fc := newFastCaddy()
+
+		fmt.Printf("正在添加通配符域名: *.%s\n", args[0])
+		if err := fc.Domain(args[0]).Ensure(); err != nil {
+			return fmt.Errorf("添加通配符域名失败: %w", err)
+		}
+
+		fmt.Printf("✓ 通配符域名添加成功\n")
+		return nil
+	},
+}
+
+// domainListCmd 列出子域名子命令
+var domainListCmd = &cobra.Command{
+	Use:   "list <domain>",
+	Short: "列出子域名",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		fc := newFastCaddy()
+
+		subdomains, err := fc.Domain(args[0]).Subdomains()
+		if err != nil {
+			return err
+		}
+		if len(subdomains) == 0 {
+			fmt.Printf("域名 %s 下没有子域名\n", args[0])
+			return nil
+		}
+		for _, sub := range subdomains {
+			fmt.Printf("%s -> %s\n", sub.Host, strings.Join(sub.Upstreams, ", "))
+		}
+		return nil
+	},
+}
+
+// domainSetCmd 添加或更新子域名子命令
+var domainSetCmd = &cobra.Command{
+	Use:   "set <domain> <subdomain>",
+	Short: "添加或更新子域名反向代理",
+	Args:  cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		upstreams := utils.SplitList(domainTo)
+		if len(upstreams) == 0 {
+			return fmt.Errorf("必须指定 --to 参数")
+		}
+
+		fc := newFastCaddy()
+
+		fmt.Printf("正在设置子域名: %s.%s -> %s\n", args[1], args[0], strings.Join(upstreams, ", "))
+		err := fc.Domain(args[0]).SetSubdomain(args[1], upstreams, routes.ProxyOptions{
+			Compress: domainCompress,
+		})
+		if err != nil {
+			return fmt.Errorf("设置子域名失败: %w", err)
+		}
+
+		fmt.Printf("✓ 子域名设置成功\n")
+		return nil
+	},
+}
+
+// domainRemoveCmd 删除子域名子命令
+var domainRemoveCmd = &cobra.Command{
+	Use:   "remove <domain> <subdomain>",
+	Short: "删除子域名",
+	Args:  cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		fc := newFastCaddy()
+
+		fmt.Printf("正在删除子域名: %s.%s\n", args[1], args[0])
+		if err := fc.Domain(args[0]).RemoveSubdomain(args[1]); err != nil {
+			return fmt.Errorf("删除子域名失败: %w", err)
+		}
+
+		fmt.Printf("✓ 子域名删除成功\n")
+		return nil
+	},
+}
+
+// domainFallbackCmd 设置未知子域名兜底处理子命令
+var domainFallbackCmd = &cobra.Command{
+	Use:   "fallback <domain>",
+	Short: "设置未知子域名的兜底处理",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		d := newFastCaddy().Domain(args[0])
+
+		if domainRemove {
+			fmt.Printf("正在删除 %s 的兜底路由...\n", args[0])
+			if err := d.RemoveFallback(); err != nil {
+				return fmt.Errorf("删除兜底路由失败: %w", err)
+			}
+			fmt.Printf("✓ 兜底路由已删除\n")
+			return nil
+		}
+
+		fmt.Printf("正在设置 %s 的兜底路由...\n", args[0])
+		err := d.SetFallback(routes.FallbackOptions{
+			Upstream:   domainTo,
+			StatusCode: domainStatus,
+			Body:       domainBody,
+		})
+		if err != nil {
+			return fmt.Errorf("设置兜底路由失败: %w", err)
+		}
+
+		fmt.Printf("✓ 兜底路由设置成功\n")
+		return nil
+	},
+}
+
+func init() {
+	domainSetCmd.Flags().StringVar(&domainTo, "to", "", "上游地址，用逗号分隔（必需）")
+	domainSetCmd.Flags().BoolVar(&domainCompress, "compress", false, "启用响应压缩（zstd, gzip）")
+
+	domainFallbackCmd.Flags().StringVar(&domainTo, "to", "", "兜底上游地址（为空时返回静态响应）")
+	domainFallbackCmd.Flags().IntVar(&domainStatus, "status", 404, "静态响应状态码")
+	domainFallbackCmd.Flags().StringVar(&domainBody, "body", "", "静态响应内容")
+	domainFallbackCmd.Flags().BoolVar(&domainRemove, "remove", false, "删除兜底路由")
+
+	domainCmd.AddCommand(domainAddCmd)
+	domainCmd.AddCommand(domainListCmd)
+	domainCmd.AddCommand(domainSetCmd)
+	domainCmd.AddCommand(domainRemoveCmd)
+	domainCmd.AddCommand(domainFallbackCmd)
+	rootCmd.AddCommand(domainCmd)
+}
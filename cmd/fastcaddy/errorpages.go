@@ -0,0 +1,153 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/youfun/fastcaddy/internal/routes"
+)
+
+var (
+	// error-pages 命令参数
+	errorPagesServer string
+	errorPagesHost   string
+	errorPagesDir    string
+	errorPagesFiles  []string
+)
+
+// errorPagesCmd 错误页面命令
+var errorPagesCmd = &cobra.Command{
+	Use:   "error-pages",
+	Short: "管理自定义错误页面",
+	Long: `为主机配置自定义的 404/5xx 错误页面，替代 Caddy 默认的空白错误响应。
+
+使用 --dir 时，目录中名为 <状态码>.html 的文件会作为对应状态码的错误页面，
+error.html 作为其他错误的默认页面。使用 --page 时，页面内容会内联到配置中。
+
+示例:
+  fastcaddy error-pages set --host example.com --dir /srv/errors
+  fastcaddy error-pages set --host example.com --page 404=./404.html --page 502=./502.html
+  fastcaddy error-pages set --page 0=./error.html
+  fastcaddy error-pages remove --host example.com`,
+}
+
+// errorPagesSetCmd 设置错误页面子命令
+var errorPagesSetCmd = &cobra.Command{
+	Use:   "set",
+	Short: "设置错误页面",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if (errorPagesDir == "") == (len(errorPagesFiles) == 0) {
+			return fmt.Errorf("必须指定 --dir 或 --page 其中之一")
+		}
+
+		var pages map[int]string
+		opts := routes.ErrorPageOptions{Host: errorPagesHost}
+
+		var err error
+		if errorPagesDir != "" {
+			opts.Root, err = filepath.Abs(errorPagesDir)
+			if err != nil {
+				return fmt.Errorf("无效的目录: %w", err)
+			}
+			pages, err = scanErrorPages(opts.Root)
+		} else {
+			pages, err = readErrorPages(errorPagesFiles)
+		}
+		if err != nil {
+			return err
+		}
+
+		fc := newFastCaddy()
+
+		fmt.Printf("正在设置 %d 个错误页面...\n", len(pages))
+		if err := fc.SetErrorPages(errorPagesServer, pages, opts); err != nil {
+			return fmt.Errorf("设置错误页面失败: %w", err)
+		}
+
+		fmt.Printf("✓ 错误页面设置成功\n")
+		return nil
+	},
+}
+
+// errorPagesRemoveCmd 移除错误页面子命令
+var errorPagesRemoveCmd = &cobra.Command{
+	Use:   "remove",
+	Short: "移除错误页面",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		fc := newFastCaddy()
+
+		if err := fc.RemoveErrorPages(errorPagesHost); err != nil {
+			return fmt.Errorf("移除错误页面失败: %w", err)
+		}
+
+		fmt.Printf("✓ 错误页面已移除\n")
+		return nil
+	},
+}
+
+// scanErrorPages 扫描目录中的错误页面文件
+// <状态码>.html 对应具体状态码，error.html 对应默认页面
+func scanErrorPages(dir string) (map[int]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("读取目录失败: %w", err)
+	}
+
+	pages := make(map[int]string)
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || filepath.Ext(name) != ".html" {
+			continue
+		}
+		base := strings.TrimSuffix(name, ".html")
+		if base == "error" {
+			pages[0] = name
+			continue
+		}
+		if code, err := strconv.Atoi(base); err == nil {
+			pages[code] = name
+		}
+	}
+
+	if len(pages) == 0 {
+		return nil, fmt.Errorf("目录 %s 中没有找到错误页面 (<状态码>.html 或 error.html)", dir)
+	}
+	return pages, nil
+}
+
+// readErrorPages 读取 状态码=文件 形式的页面参数，返回内联页面内容
+func readErrorPages(specs []string) (map[int]string, error) {
+	pages := make(map[int]string)
+	for _, spec := range specs {
+		codeStr, file, ok := strings.Cut(spec, "=")
+		if !ok {
+			return nil, fmt.Errorf("无效的页面参数: %s (应为 状态码=文件)", spec)
+		}
+		code, err := strconv.Atoi(strings.TrimSpace(codeStr))
+		if err != nil {
+			return nil, fmt.Errorf("无效的状态码: %s", codeStr)
+		}
+		content, err := os.ReadFile(strings.TrimSpace(file))
+		if err != nil {
+			return nil, fmt.Errorf("读取页面文件失败: %w", err)
+		}
+		pages[code] = string(content)
+	}
+	return pages, nil
+}
+
+func init() {
+	errorPagesCmd.PersistentFlags().StringVar(&errorPagesServer, "server", "srv0", "服务器名称")
+	errorPagesCmd.PersistentFlags().StringVar(&errorPagesHost, "host", "", "主机名（为空时对所有主机生效）")
+
+	errorPagesSetCmd.Flags().StringVar(&errorPagesDir, "dir", "", "错误页面目录")
+	errorPagesSetCmd.Flags().StringArrayVar(&errorPagesFiles, "page", nil, "内联错误页面，格式为 状态码=文件，可重复指定（0 表示默认页面）")
+
+	errorPagesCmd.AddCommand(errorPagesSetCmd)
+	errorPagesCmd.AddCommand(errorPagesRemoveCmd)
+	rootCmd.AddCommand(errorPagesCmd)
+}
@@ -0,0 +1,56 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/youfun/fastcaddy/internal/export"
+)
+
+var (
+	// export 命令参数
+	exportFormat string
+	exportOutput string
+)
+
+// exportCmd 导出配置命令
+var exportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "导出正在运行的配置",
+	Long: `导出正在运行的 Caddy 配置，用于审阅和编写文档。
+
+caddyfile 格式为近似转换，覆盖 fastcaddy 生成的处理器 (reverse_proxy、subroute、
+file_server、static_response、headers 等)，无法表达的部分输出为注释，不保证能够原样导入。
+json 和 yaml 格式为完整配置 (包含 API 令牌和密码哈希，请注意保管)。
+
+示例:
+  fastcaddy export
+  fastcaddy export --format yaml -o caddy.yaml
+  fastcaddy export --format json`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		fc := newFastCaddy()
+		out, err := fc.ExportConfig(exportFormat)
+		if err != nil {
+			return fmt.Errorf("导出配置失败: %w", err)
+		}
+
+		if exportOutput == "" {
+			fmt.Print(string(out))
+			return nil
+		}
+		if err := os.WriteFile(exportOutput, out, 0600); err != nil {
+			return fmt.Errorf("写入 %s 失败: %w", exportOutput, err)
+		}
+		fmt.Printf("✓ 配置已导出到 %s\n", exportOutput)
+		return nil
+	},
+}
+
+func init() {
+	exportCmd.Flags().StringVar(&exportFormat, "format", export.FormatCaddyfile, "导出格式 ("+strings.Join(export.Formats, "|")+")")
+	exportCmd.Flags().StringVarP(&exportOutput, "output", "o", "", "输出文件（默认输出到标准输出）")
+
+	rootCmd.AddCommand(exportCmd)
+}
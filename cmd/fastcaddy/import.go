@@ -0,0 +1,114 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/youfun/fastcaddy/internal/api"
+	"github.com/youfun/fastcaddy/internal/routes"
+)
+
+var (
+	// import 命令参数
+	importCaddyfile string
+	importMerge     bool
+	importReplace   bool
+	importServer    string
+	importDryRun    bool
+)
+
+// importCmd 导入 Caddyfile 命令
+var importCmd = &cobra.Command{
+	Use:   "import",
+	Short: "导入 Caddyfile",
+	Long: `通过 Caddy 的 /adapt 接口将 Caddyfile 转换为 JSON，并为路由分配 fastcaddy 风格的 @id
+(根据主机名和路径生成)，之后可以使用 del-proxy、protect 等命令按 ID 管理。
+
+--merge (默认) 合并到正在运行的配置：分配的 ID 不与已有的 @id 重复，路由追加到服务器末尾，
+显式指定了 @id 的路由在同一服务器中原地替换，TLS 自动化策略按 subjects 合并，其他应用仅在不存在时添加；
+合并在事务中完成，任一步骤失败时不修改配置。使用 --replace 时整体替换正在运行的配置。
+
+示例:
+  fastcaddy import --caddyfile ./Caddyfile --dry-run
+  fastcaddy import --caddyfile ./Caddyfile --merge
+  fastcaddy import --caddyfile ./Caddyfile --replace`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if importMerge && importReplace {
+			return fmt.Errorf("--merge 和 --replace 不能同时使用")
+		}
+		body, err := os.ReadFile(importCaddyfile)
+		if err != nil {
+			return fmt.Errorf("读取 Caddyfile 失败: %w", err)
+		}
+
+		fc := newFastCaddy()
+
+		if importDryRun {
+			adapted, err := fc.API.Adapt(body, "caddyfile")
+			if err != nil {
+				return err
+			}
+			printAdaptWarnings(adapted.Warnings)
+
+			var cfg map[string]interface{}
+			if err := json.Unmarshal(adapted.Result, &cfg); err != nil {
+				return fmt.Errorf("解析转换结果失败: %w", err)
+			}
+			if err := fc.Routes.AssignImportIDs(cfg, routes.ImportOptions{Replace: importReplace}); err != nil {
+				return err
+			}
+			out, err := json.MarshalIndent(cfg, "", "  ")
+			if err != nil {
+				return err
+			}
+			fmt.Println(string(out))
+			return nil
+		}
+
+		fmt.Printf("正在导入 %s...\n", importCaddyfile)
+		result, warnings, err := fc.ImportCaddyfile(body, routes.ImportOptions{
+			ServerName: importServer,
+			Replace:    importReplace,
+		})
+		printAdaptWarnings(warnings)
+		if err != nil {
+			return fmt.Errorf("导入 Caddyfile 失败: %w", err)
+		}
+
+		if len(result.Added) > 0 {
+			fmt.Printf("新增路由: %s\n", strings.Join(result.Added, ", "))
+		}
+		if len(result.Replaced) > 0 {
+			fmt.Printf("替换路由: %s\n", strings.Join(result.Replaced, ", "))
+		}
+		if len(result.Merged) > 0 {
+			fmt.Printf("合并配置: %s\n", strings.Join(result.Merged, ", "))
+		}
+		if len(result.Skipped) > 0 {
+			fmt.Printf("已存在，跳过: %s\n", strings.Join(result.Skipped, ", "))
+		}
+		fmt.Printf("✓ Caddyfile 导入成功\n")
+		return nil
+	},
+}
+
+// printAdaptWarnings 输出配置转换警告
+func printAdaptWarnings(warnings []api.AdaptWarning) {
+	for _, w := range warnings {
+		fmt.Fprintf(os.Stderr, "警告: %s\n", w)
+	}
+}
+
+func init() {
+	importCmd.Flags().StringVar(&importCaddyfile, "caddyfile", "", "Caddyfile 路径（必需）")
+	importCmd.Flags().BoolVar(&importReplace, "replace", false, "整体替换正在运行的配置，而不是合并")
+	importCmd.Flags().BoolVar(&importMerge, "merge", false, "合并到正在运行的配置（默认）")
+	importCmd.Flags().StringVar(&importServer, "server", "", "合并到的服务器名称（默认与 Caddyfile 中的服务器同名）")
+	importCmd.Flags().BoolVar(&importDryRun, "dry-run", false, "只输出转换并分配 ID 后的 JSON，不修改配置")
+	importCmd.MarkFlagRequired("caddyfile")
+
+	rootCmd.AddCommand(importCmd)
+}
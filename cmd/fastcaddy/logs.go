@@ -0,0 +1,298 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"sort"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/youfun/fastcaddy/internal/logging"
+	"github.com/youfun/fastcaddy/internal/utils"
+	"github.com/youfun/fastcaddy/pkg/types"
+)
+
+var (
+	// logs 命令参数
+	logsServer       string
+	logsHost         string
+	logsName         string
+	logsFile         string
+	logsStdout       bool
+	logsNet          string
+	logsFormat       string
+	logsLevel        string
+	logsRollSize     int
+	logsRollKeep     int
+	logsRollKeepDays int
+	logsNoRoll       bool
+
+	// logs tail 命令参数
+	tailStatus     string
+	tailPath       string
+	tailMinLatency time.Duration
+	tailSince      string
+	tailNoFollow   bool
+	tailSummary    bool
+	tailTop        int
+)
+
+// logsCmd 访问日志命令
+var logsCmd = &cobra.Command{
+	Use:   "logs",
+	Short: "管理访问日志",
+	Long: `为主机启用或关闭访问日志。每个主机写入独立的命名日志，
+支持文件 (带滚动)、标准输出和网络输出，以及 JSON/console 编码格式。
+
+示例:
+  fastcaddy logs enable --host api.example.com --file /var/log/caddy/api.log
+  fastcaddy logs enable --host api.example.com --file /var/log/caddy/api.log --roll-size 50 --roll-keep 10
+  fastcaddy logs enable --host www.example.com --stdout --format console
+  fastcaddy logs enable --host api.example.com --net tcp/logs.example.com:5140
+  fastcaddy logs disable --host api.example.com
+  fastcaddy logs list
+  fastcaddy logs tail --host api.example.com --status 5xx
+  fastcaddy logs tail --host api.example.com --since 1h --no-follow --summary`,
+}
+
+// logsEnableCmd 启用访问日志子命令
+var logsEnableCmd = &cobra.Command{
+	Use:   "enable",
+	Short: "为主机启用访问日志",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if !utils.ValidateHost(logsHost) {
+			return fmt.Errorf("无效的主机名: %s", logsHost)
+		}
+
+		writer, err := buildLogWriter()
+		if err != nil {
+			return err
+		}
+		opts := logging.AccessLogOptions{
+			Name:   logsName,
+			Writer: writer,
+			Format: logsFormat,
+			Level:  logsLevel,
+		}
+
+		fc := newFastCaddy()
+
+		fmt.Printf("正在为 %s 启用访问日志...\n", logsHost)
+		if err := fc.EnableAccessLog(logsServer, logsHost, opts); err != nil {
+			return fmt.Errorf("启用访问日志失败: %w", err)
+		}
+
+		fmt.Printf("✓ 访问日志已启用\n")
+		return nil
+	},
+}
+
+// logsDisableCmd 关闭访问日志子命令
+var logsDisableCmd = &cobra.Command{
+	Use:   "disable",
+	Short: "关闭主机的访问日志",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if logsHost == "" {
+			return fmt.Errorf("必须指定主机名")
+		}
+
+		fc := newFastCaddy()
+
+		fmt.Printf("正在关闭 %s 的访问日志...\n", logsHost)
+		if err := fc.DisableAccessLog(logsServer, logsHost); err != nil {
+			return fmt.Errorf("关闭访问日志失败: %w", err)
+		}
+
+		fmt.Printf("✓ 访问日志已关闭\n")
+		return nil
+	},
+}
+
+// logsListCmd 列出访问日志子命令
+var logsListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "列出已启用访问日志的主机",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		fc := newFastCaddy()
+
+		logs, err := fc.Logging.GetAccessLogs(logsServer)
+		if err != nil {
+			return err
+		}
+		if logs == nil || len(logs.LoggerNames) == 0 {
+			fmt.Printf("服务器 %s 未启用主机访问日志\n", logsServer)
+			return nil
+		}
+
+		hosts := make([]string, 0, len(logs.LoggerNames))
+		for host := range logs.LoggerNames {
+			hosts = append(hosts, host)
+		}
+		sort.Strings(hosts)
+		for _, host := range hosts {
+			fmt.Printf("%s -> %v\n", host, logs.LoggerNames[host])
+		}
+		return nil
+	},
+}
+
+// logsTailCmd 跟踪访问日志子命令
+var logsTailCmd = &cobra.Command{
+	Use:   "tail",
+	Short: "跟踪并过滤访问日志",
+	Long: `读取 Caddy 的 JSON 访问日志文件，按主机、状态码、路径、耗时和时间过滤后输出，
+或汇总统计 (错误率、最常访问的路径)。未指定 --file 时根据主机的日志配置查找文件。
+
+示例:
+  fastcaddy logs tail --host api.example.com
+  fastcaddy logs tail --host api.example.com --status 5xx,429 --path /v1/
+  fastcaddy logs tail --file /var/log/caddy/api.log --min-latency 500ms
+  fastcaddy logs tail --host api.example.com --since 2h --no-follow --summary`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		status, err := logging.ParseStatusFilter(tailStatus)
+		if err != nil {
+			return err
+		}
+		filter := logging.AccessFilter{
+			Host:       logsHost,
+			Status:     status,
+			PathPrefix: tailPath,
+			MinLatency: tailMinLatency,
+		}
+		if tailSince != "" {
+			if filter.Since, err = parseSince(tailSince); err != nil {
+				return err
+			}
+		}
+
+		filename := logsFile
+		if filename == "" {
+			if logsHost == "" {
+				return fmt.Errorf("必须指定 --host 或 --file")
+			}
+			fc := newFastCaddy()
+			if filename, err = fc.Logging.AccessLogFile(logsServer, logsHost); err != nil {
+				return err
+			}
+		}
+
+		opts := logging.TailOptions{
+			Follow:    !tailNoFollow,
+			FromStart: tailNoFollow || tailSince != "",
+		}
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+		defer stop()
+
+		var summary logging.AccessSummary
+		err = logging.TailAccessLog(ctx, filename, opts, func(entry logging.AccessEntry) {
+			if !filter.Match(entry) {
+				return
+			}
+			if tailSummary {
+				summary.Add(entry)
+				return
+			}
+			fmt.Println(entry)
+		})
+		if err != nil {
+			return err
+		}
+
+		if tailSummary {
+			printAccessSummary(&summary, tailTop)
+		}
+		return nil
+	},
+}
+
+// parseSince 解析起始时间，支持相对时长 (如 "1h") 或 RFC3339 时间
+func parseSince(s string) (time.Time, error) {
+	if d, err := time.ParseDuration(s); err == nil {
+		return time.Now().Add(-d), nil
+	}
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("无效的起始时间: %s (示例: 1h 或 2006-01-02T15:04:05Z)", s)
+	}
+	return t, nil
+}
+
+// printAccessSummary 输出访问日志统计
+func printAccessSummary(summary *logging.AccessSummary, top int) {
+	fmt.Printf("请求总数: %d\n", summary.Total)
+	if summary.Total == 0 {
+		return
+	}
+	fmt.Printf("4xx: %d, 5xx: %d, 错误率: %.2f%%\n", summary.ClientErrors, summary.ServerErrors, summary.ErrorRate()*100)
+	fmt.Printf("平均耗时: %s\n", summary.AverageLatency().Round(time.Microsecond))
+	fmt.Printf("最常访问的路径:\n")
+	for _, p := range summary.TopPaths(top) {
+		fmt.Printf("  %6d  %s\n", p.Count, p.Path)
+	}
+}
+
+// buildLogWriter 根据命令行参数构建日志输出
+func buildLogWriter() (types.LogWriter, error) {
+	outputs := 0
+	for _, set := range []bool{logsFile != "", logsStdout, logsNet != ""} {
+		if set {
+			outputs++
+		}
+	}
+	if outputs > 1 {
+		return types.LogWriter{}, fmt.Errorf("--file、--stdout 和 --net 只能指定一个")
+	}
+
+	switch {
+	case logsFile != "":
+		writer := types.LogWriter{
+			Output:       "file",
+			Filename:     logsFile,
+			RollSizeMB:   logsRollSize,
+			RollKeep:     logsRollKeep,
+			RollKeepDays: logsRollKeepDays,
+		}
+		if logsNoRoll {
+			roll := false
+			writer.Roll = &roll
+		}
+		return writer, nil
+	case logsNet != "":
+		return types.LogWriter{Output: "net", Address: logsNet}, nil
+	default:
+		return types.LogWriter{Output: "stdout"}, nil
+	}
+}
+
+func init() {
+	logsCmd.PersistentFlags().StringVar(&logsServer, "server", "srv0", "服务器名称")
+	logsCmd.PersistentFlags().StringVar(&logsHost, "host", "", "主机名")
+
+	logsEnableCmd.Flags().StringVar(&logsName, "name", "", "日志名称 (默认根据主机名生成)")
+	logsEnableCmd.Flags().StringVar(&logsFile, "file", "", "写入指定日志文件")
+	logsEnableCmd.Flags().BoolVar(&logsStdout, "stdout", false, "写入标准输出 (默认)")
+	logsEnableCmd.Flags().StringVar(&logsNet, "net", "", "写入网络地址 (如 tcp/logs.example.com:5140)")
+	logsEnableCmd.Flags().StringVar(&logsFormat, "format", "json", "编码格式 (json, console)")
+	logsEnableCmd.Flags().StringVar(&logsLevel, "level", "", "最低日志级别 (如 INFO, ERROR)")
+	logsEnableCmd.Flags().IntVar(&logsRollSize, "roll-size", 0, "单个日志文件的最大大小 (MB)")
+	logsEnableCmd.Flags().IntVar(&logsRollKeep, "roll-keep", 0, "保留的日志文件数量")
+	logsEnableCmd.Flags().IntVar(&logsRollKeepDays, "roll-keep-days", 0, "日志文件保留天数")
+	logsEnableCmd.Flags().BoolVar(&logsNoRoll, "no-roll", false, "禁用日志滚动")
+
+	logsTailCmd.Flags().StringVar(&logsFile, "file", "", "访问日志文件 (默认根据主机的日志配置查找)")
+	logsTailCmd.Flags().StringVar(&tailStatus, "status", "", "状态码过滤，用逗号分隔 (如 404,5xx)")
+	logsTailCmd.Flags().StringVar(&tailPath, "path", "", "请求路径前缀")
+	logsTailCmd.Flags().DurationVar(&tailMinLatency, "min-latency", 0, "最小处理耗时 (如 500ms)")
+	logsTailCmd.Flags().StringVar(&tailSince, "since", "", "起始时间 (如 1h 或 RFC3339 时间)")
+	logsTailCmd.Flags().BoolVar(&tailNoFollow, "no-follow", false, "读取完现有日志后退出")
+	logsTailCmd.Flags().BoolVar(&tailSummary, "summary", false, "只输出统计汇总")
+	logsTailCmd.Flags().IntVar(&tailTop, "top", 10, "汇总中显示的路径数量")
+
+	logsCmd.AddCommand(logsEnableCmd)
+	logsCmd.AddCommand(logsDisableCmd)
+	logsCmd.AddCommand(logsListCmd)
+	logsCmd.AddCommand(logsTailCmd)
+	rootCmd.AddCommand(logsCmd)
+}
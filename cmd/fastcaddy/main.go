@@ -5,13 +5,20 @@ import (
 	"os"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
 	"github.com/youfun/fastcaddy"
+	"github.com/youfun/fastcaddy/internal/api"
+	"github.com/youfun/fastcaddy/internal/routes"
 	"github.com/youfun/fastcaddy/internal/utils"
+	"github.com/youfun/fastcaddy/pkg/types"
 )
 
 var (
+	// 全局参数
+	skipValidation bool
+
 	// 全局变量存储命令行参数
 	cfToken      string
 	serverName   string
@@ -24,6 +31,38 @@ var (
 	ports        string
 	host         string
 	routeID      string
+
+	// 服务器协议选项
+	protocols          string
+	proxyProtocol      bool
+	proxyProtocolAllow string
+	trustedProxies     string
+	readTimeout        string
+	readHeaderTimeout  string
+	writeTimeout       string
+	idleTimeout        string
+
+	// 反向代理选项
+	secureHeaders bool
+	upstreamHost  bool
+	compress      bool
+	proxyPath     string
+	stripPrefix   string
+	rewriteURI    string
+	proxyProfile  string
+
+	// 上游传输选项
+	tlsInsecure       bool
+	tlsServerName     string
+	tlsTrustedCA      string
+	transportVersions string
+	dialTimeout       string
+	responseTimeout   string
+
+	// 请求限制选项
+	maxBody string
+	rate    string
+	rateKey string
 )
 
 // rootCmd 根命令 - FastCaddy CLI 工具的主入口
@@ -46,9 +85,20 @@ var setupCmd = &cobra.Command{
 	Short: "设置 Caddy 基本配置",
 	Long: `初始化 Caddy 的基本配置，包括 SSL/TLS 设置和 HTTP 服务器配置。
 
-可以配置为本地开发环境（使用内部证书）或生产环境（使用 ACME/Let's Encrypt）。`,
+可以配置为本地开发环境（使用内部证书）或生产环境（使用 ACME/Let's Encrypt）。
+
+示例:
+  fastcaddy setup --local --protocols h1,h2,h3
+  fastcaddy setup --proxy-protocol --trusted-proxies 10.0.0.0/8
+  fastcaddy setup --read-timeout 30s --idle-timeout 5m`,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		fc := fastcaddy.New()
+		// 解析服务器协议选项
+		serverOptions, hasOptions, err := buildServerOptions()
+		if err != nil {
+			return err
+		}
+
+		fc := newFastCaddy()
 
 		// 如果没有提供 CF Token，尝试从环境变量获取
 		if cfToken == "" && !isLocal {
@@ -66,7 +116,14 @@ var setupCmd = &cobra.Command{
 			}
 		}
 
-		err := fc.SetupCaddy(cfToken, serverName, isLocal, installTrust)
+		// 服务器协议选项与基本配置在同一事务中应用
+		var options *types.HTTPServer
+		if hasOptions {
+			fmt.Printf("同时更新服务器 %s 的协议选项\n", serverName)
+			options = &serverOptions
+		}
+
+		err = fc.SetupCaddyWithOptions(cfToken, serverName, isLocal, installTrust, options)
 		if err != nil {
 			return fmt.Errorf("设置 Caddy 失败: %w", err)
 		}
@@ -76,6 +133,60 @@ var setupCmd = &cobra.Command{
 	},
 }
 
+// buildServerOptions 根据 setup 命令参数构建服务器选项
+// 返回的布尔值表示是否设置了任何选项
+func buildServerOptions() (types.HTTPServer, bool, error) {
+	var options types.HTTPServer
+	hasOptions := false
+
+	if protocols != "" {
+		for _, protocol := range utils.SplitList(protocols) {
+			if !utils.ValidateProtocol(protocol) {
+				return options, false, fmt.Errorf("无效的协议: %s (可选: h1, h2, h2c, h3)", protocol)
+			}
+			options.Protocols = append(options.Protocols, protocol)
+		}
+		hasOptions = true
+	}
+
+	if proxyProtocol {
+		options.ListenerWrappers = routes.ProxyProtocolWrappers("", utils.SplitList(proxyProtocolAllow))
+		hasOptions = true
+	}
+
+	if trustedProxies != "" {
+		options.TrustedProxies = &types.TrustedProxies{
+			Source: "static",
+			Ranges: utils.SplitList(trustedProxies),
+		}
+		hasOptions = true
+	}
+
+	// 校验并设置超时参数
+	timeouts := []struct {
+		name   string
+		value  string
+		target *types.Duration
+	}{
+		{"read-timeout", readTimeout, &options.ReadTimeout},
+		{"read-header-timeout", readHeaderTimeout, &options.ReadHeaderTimeout},
+		{"write-timeout", writeTimeout, &options.WriteTimeout},
+		{"idle-timeout", idleTimeout, &options.IdleTimeout},
+	}
+	for _, t := range timeouts {
+		if t.value == "" {
+			continue
+		}
+		if _, err := time.ParseDuration(t.value); err != nil {
+			return options, false, fmt.Errorf("无效的 --%s: %s", t.name, t.value)
+		}
+		*t.target = types.Duration(t.value)
+		hasOptions = true
+	}
+
+	return options, hasOptions, nil
+}
+
 // addProxyCmd 添加反向代理命令
 var addProxyCmd = &cobra.Command{
 	Use:   "add-proxy",
@@ -84,7 +195,14 @@ var addProxyCmd = &cobra.Command{
 
 示例:
   fastcaddy add-proxy --from api.example.com --to localhost:8080
-  fastcaddy add-proxy --from web.example.com --to 127.0.0.1:3000`,
+  fastcaddy add-proxy --from web.example.com --to 127.0.0.1:3000
+  fastcaddy add-proxy --from app.example.com --to localhost:3000 --secure-headers --compress
+  fastcaddy add-proxy --from api.example.com --path /v1/* --strip-prefix /v1 --to localhost:9000
+  fastcaddy add-proxy --from ws.example.com --to localhost:8081 --profile websocket
+  fastcaddy add-proxy --from internal.example.com --to https://10.0.0.5:8443 --tls-insecure
+  fastcaddy add-proxy --from grpc.example.com --to h2c://localhost:50051
+  fastcaddy add-proxy --from api.example.com --to localhost:8080 --max-body 10MB --rate 100r/m
+  fastcaddy add-proxy --from api.example.com --to localhost:8080 --rate 1000r/h --rate-key header:X-API-Key`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		if fromHost == "" || toURL == "" {
 			return fmt.Errorf("必须指定 --from 和 --to 参数")
@@ -98,10 +216,38 @@ var addProxyCmd = &cobra.Command{
 			return fmt.Errorf("无效的目标 URL: %s", toURL)
 		}
 
-		fc := fastcaddy.New()
+		opts := routes.ProxyOptions{
+			Path: proxyPath,
+			Rewrite: routes.RewriteOptions{
+				URI:         rewriteURI,
+				StripPrefix: stripPrefix,
+			},
+			SecureHeaders: secureHeaders,
+			Compress:      compress,
+		}
+		if upstreamHost {
+			opts.RequestHeaders = routes.RewriteUpstreamHost()
+		}
+		if proxyProfile != "" {
+			profile, err := routes.ProxyProfile(proxyProfile)
+			if err != nil {
+				return err
+			}
+			opts.Streaming = &profile
+		}
+		transport, err := buildTransport()
+		if err != nil {
+			return err
+		}
+		opts.Transport = transport
+		if err := applyLimits(&opts); err != nil {
+			return err
+		}
+
+		fc := newFastCaddy()
 
 		fmt.Printf("正在添加反向代理: %s -> %s\n", fromHost, toURL)
-		err := fc.AddReverseProxy(fromHost, toURL)
+		err = fc.AddReverseProxyWithOptions(fromHost, toURL, opts)
 		if err != nil {
 			return fmt.Errorf("添加反向代理失败: %w", err)
 		}
@@ -111,6 +257,86 @@ var addProxyCmd = &cobra.Command{
 	},
 }
 
+// applyLimits 根据 add-proxy 命令参数设置请求体大小限制和限流
+func applyLimits(opts *routes.ProxyOptions) error {
+	if maxBody != "" {
+		size, err := utils.ParseSize(maxBody)
+		if err != nil {
+			return err
+		}
+		opts.MaxBodySize = size
+	}
+
+	if rate == "" {
+		return nil
+	}
+	rateLimit, err := routes.ParseRate(rate)
+	if err != nil {
+		return err
+	}
+	switch {
+	case rateKey == "" || rateKey == "ip":
+		rateLimit.Key = routes.ClientIPKey
+	case strings.HasPrefix(rateKey, "header:") && len(rateKey) > len("header:"):
+		rateLimit.Key = routes.HeaderKey(strings.TrimPrefix(rateKey, "header:"))
+	default:
+		return fmt.Errorf("无效的限流键: %s (可选: ip, header:<名称>)", rateKey)
+	}
+	opts.RateLimit = &rateLimit
+	return nil
+}
+
+// buildTransport 根据 add-proxy 命令参数构建上游传输配置
+// 未设置任何传输参数时返回 nil
+func buildTransport() (*types.Transport, error) {
+	transport := &types.Transport{Protocol: "http"}
+	hasOptions := false
+
+	if tlsInsecure || tlsServerName != "" || tlsTrustedCA != "" {
+		transport.TLS = &types.TransportTLS{
+			ServerName:         tlsServerName,
+			InsecureSkipVerify: tlsInsecure,
+		}
+		if tlsTrustedCA != "" {
+			transport.TLS.CA = &types.CAPool{
+				Provider: "file",
+				PEMFiles: utils.SplitList(tlsTrustedCA),
+			}
+		}
+		hasOptions = true
+	}
+
+	if transportVersions != "" {
+		transport.Versions = utils.SplitList(transportVersions)
+		hasOptions = true
+	}
+
+	// 校验并设置超时参数
+	timeouts := []struct {
+		name   string
+		value  string
+		target *types.Duration
+	}{
+		{"dial-timeout", dialTimeout, &transport.DialTimeout},
+		{"response-timeout", responseTimeout, &transport.ResponseHeaderTimeout},
+	}
+	for _, t := range timeouts {
+		if t.value == "" {
+			continue
+		}
+		if _, err := time.ParseDuration(t.value); err != nil {
+			return nil, fmt.Errorf("无效的 --%s: %s", t.name, t.value)
+		}
+		*t.target = types.Duration(t.value)
+		hasOptions = true
+	}
+
+	if !hasOptions {
+		return nil, nil
+	}
+	return transport, nil
+}
+
 // delProxyCmd 删除反向代理命令
 var delProxyCmd = &cobra.Command{
 	Use:   "del-proxy",
@@ -124,7 +350,7 @@ var delProxyCmd = &cobra.Command{
 			return fmt.Errorf("必须指定 --id 参数")
 		}
 
-		fc := fastcaddy.New()
+		fc := newFastCaddy()
 
 		if !fc.HasID(routeID) {
 			return fmt.Errorf("路由 ID '%s' 不存在", routeID)
@@ -154,7 +380,7 @@ var addWildcardCmd = &cobra.Command{
 			return fmt.Errorf("必须指定 --domain 参数")
 		}
 
-		fc := fastcaddy.New()
+		fc := newFastCaddy()
 
 		fmt.Printf("正在添加通配符路由: *.%s\n", domain)
 		err := fc.AddWildcardRoute(domain)
@@ -175,7 +401,7 @@ var addSubProxyCmd = &cobra.Command{
 
 示例:
   fastcaddy add-sub-proxy --domain example.com --subdomain api --ports 8080 --host localhost
-  fastcaddy add-sub-proxy --domain example.com --subdomain web --ports 3000,3001`,
+  fastcaddy add-sub-proxy --domain example.com --subdomain web --ports 3000,3001 --compress`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		if domain == "" || subdomain == "" || ports == "" {
 			return fmt.Errorf("必须指定 --domain, --subdomain 和 --ports 参数")
@@ -195,10 +421,12 @@ var addSubProxyCmd = &cobra.Command{
 			}
 		}
 
-		fc := fastcaddy.New()
+		fc := newFastCaddy()
 
 		fmt.Printf("正在添加子域名反向代理: %s.%s -> %s:%s\n", subdomain, domain, host, ports)
-		err := fc.AddSubReverseProxy(domain, subdomain, portList, host)
+		err := fc.AddSubReverseProxyWithOptions(domain, subdomain, portList, host, routes.ProxyOptions{
+			Compress: compress,
+		})
 		if err != nil {
 			return fmt.Errorf("添加子域名反向代理失败: %w", err)
 		}
@@ -214,7 +442,7 @@ var statusCmd = &cobra.Command{
 	Short: "查看 Caddy 配置状态",
 	Long:  `显示当前 Caddy 配置的状态信息。`,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		fc := fastcaddy.New()
+		fc := newFastCaddy()
 
 		// 检查各个配置路径是否存在
 		fmt.Printf("Caddy 配置状态:\n")
@@ -242,11 +470,29 @@ var statusCmd = &cobra.Command{
 	},
 }
 
+// newFastCaddy 根据全局参数创建 FastCaddy 客户端
+func newFastCaddy() *fastcaddy.FastCaddy {
+	client := api.NewClient()
+	client.SkipValidation = skipValidation
+	return fastcaddy.NewWithClient(client)
+}
+
 func init() {
+	// 全局参数
+	rootCmd.PersistentFlags().BoolVar(&skipValidation, "skip-validation", false, "写入前跳过本地配置校验")
+
 	// 设置命令参数
 	setupCmd.Flags().StringVar(&cfToken, "cf-token", "", "Cloudflare API 令牌（用于 ACME DNS 挑战）")
 	setupCmd.Flags().StringVar(&serverName, "server", "srv0", "服务器名称")
 	setupCmd.Flags().BoolVar(&isLocal, "local", false, "是否为本地开发环境（使用内部证书）")
+	setupCmd.Flags().StringVar(&protocols, "protocols", "", "启用的协议，用逗号分隔（h1,h2,h2c,h3）")
+	setupCmd.Flags().BoolVar(&proxyProtocol, "proxy-protocol", false, "启用 PROXY protocol 监听器（负载均衡器后部署）")
+	setupCmd.Flags().StringVar(&proxyProtocolAllow, "proxy-protocol-allow", "", "允许发送 PROXY 头的 CIDR 列表，用逗号分隔")
+	setupCmd.Flags().StringVar(&trustedProxies, "trusted-proxies", "", "受信任代理的 CIDR 列表，用逗号分隔")
+	setupCmd.Flags().StringVar(&readTimeout, "read-timeout", "", "读取请求超时时间（如 30s）")
+	setupCmd.Flags().StringVar(&readHeaderTimeout, "read-header-timeout", "", "读取请求头超时时间（如 10s）")
+	setupCmd.Flags().StringVar(&writeTimeout, "write-timeout", "", "写入响应超时时间（如 60s）")
+	setupCmd.Flags().StringVar(&idleTimeout, "idle-timeout", "", "空闲连接超时时间（如 5m）")
 	
 	// installTrust 参数需要特殊处理，因为它是一个 *bool
 	var installTrustFlag bool
@@ -260,6 +506,22 @@ func init() {
 
 	addProxyCmd.Flags().StringVar(&fromHost, "from", "", "源主机名（必需）")
 	addProxyCmd.Flags().StringVar(&toURL, "to", "", "目标 URL（必需）")
+	addProxyCmd.Flags().BoolVar(&secureHeaders, "secure-headers", false, "添加安全响应头（HSTS, X-Frame-Options 等）并移除 Server 头")
+	addProxyCmd.Flags().BoolVar(&upstreamHost, "upstream-host", false, "将发送到上游的 Host 头改写为上游地址")
+	addProxyCmd.Flags().BoolVar(&compress, "compress", false, "启用响应压缩（zstd, gzip）")
+	addProxyCmd.Flags().StringVar(&proxyPath, "path", "", "路径匹配（如 /v1/*）")
+	addProxyCmd.Flags().StringVar(&stripPrefix, "strip-prefix", "", "转发前去除的路径前缀（如 /v1）")
+	addProxyCmd.Flags().StringVar(&rewriteURI, "rewrite-uri", "", "转发前重写 URI（支持占位符）")
+	addProxyCmd.Flags().StringVar(&proxyProfile, "profile", "", "流式传输预置配置（websocket, sse）")
+	addProxyCmd.Flags().BoolVar(&tlsInsecure, "tls-insecure", false, "跳过上游证书校验（仅用于自签名的内部服务）")
+	addProxyCmd.Flags().StringVar(&tlsServerName, "tls-server-name", "", "与上游 TLS 握手使用的 SNI")
+	addProxyCmd.Flags().StringVar(&tlsTrustedCA, "tls-trusted-ca", "", "受信任的上游 CA 证书文件，用逗号分隔")
+	addProxyCmd.Flags().StringVar(&transportVersions, "transport-versions", "", "与上游通信的 HTTP 版本，用逗号分隔（1.1, 2, h2c, 3）")
+	addProxyCmd.Flags().StringVar(&dialTimeout, "dial-timeout", "", "连接上游超时时间（如 5s）")
+	addProxyCmd.Flags().StringVar(&responseTimeout, "response-timeout", "", "等待上游响应头超时时间（如 30s）")
+	addProxyCmd.Flags().StringVar(&maxBody, "max-body", "", "请求体最大大小（如 10MB）")
+	addProxyCmd.Flags().StringVar(&rate, "rate", "", "限流速率（如 100r/m，需要 rate_limit 模块）")
+	addProxyCmd.Flags().StringVar(&rateKey, "rate-key", "ip", "限流键（ip 或 header:<名称>）")
 	addProxyCmd.MarkFlagRequired("from")
 	addProxyCmd.MarkFlagRequired("to")
 
@@ -273,6 +535,7 @@ func init() {
 	addSubProxyCmd.Flags().StringVar(&subdomain, "subdomain", "", "子域名（必需）")
 	addSubProxyCmd.Flags().StringVar(&ports, "ports", "", "端口列表，用逗号分隔（必需）")
 	addSubProxyCmd.Flags().StringVar(&host, "host", "localhost", "目标主机")
+	addSubProxyCmd.Flags().BoolVar(&compress, "compress", false, "启用响应压缩（zstd, gzip）")
 	addSubProxyCmd.MarkFlagRequired("domain")
 	addSubProxyCmd.MarkFlagRequired("subdomain")
 	addSubProxyCmd.MarkFlagRequired("ports")
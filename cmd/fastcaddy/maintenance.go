@@ -0,0 +1,94 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/youfun/fastcaddy/internal/routes"
+	"github.com/youfun/fastcaddy/internal/utils"
+)
+
+var (
+	// maintenance 命令参数
+	maintenanceRetryAfter int
+	maintenanceAllow      string
+	maintenanceBodyFile   string
+)
+
+// maintenanceCmd 维护模式命令
+var maintenanceCmd = &cobra.Command{
+	Use:   "maintenance",
+	Short: "切换主机的维护模式",
+	Long: `将主机切换为 "503 Service Unavailable" 维护页面，或恢复正常服务。
+原有的代理配置不会被修改。
+
+示例:
+  fastcaddy maintenance on api.example.com --retry-after 600
+  fastcaddy maintenance on api.example.com --allow 10.0.0.0/8,192.168.1.10 --body-file ./maintenance.html
+  fastcaddy maintenance off api.example.com`,
+}
+
+// maintenanceOnCmd 开启维护模式子命令
+var maintenanceOnCmd = &cobra.Command{
+	Use:   "on <host>",
+	Short: "开启维护模式",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		host := args[0]
+		if !utils.ValidateHost(host) {
+			return fmt.Errorf("无效的主机名: %s", host)
+		}
+
+		opts := routes.MaintenanceOptions{
+			RetryAfter: maintenanceRetryAfter,
+			Allow:      utils.SplitList(maintenanceAllow),
+		}
+		if maintenanceBodyFile != "" {
+			body, err := os.ReadFile(maintenanceBodyFile)
+			if err != nil {
+				return fmt.Errorf("读取维护页面失败: %w", err)
+			}
+			opts.Body = string(body)
+		}
+
+		fc := newFastCaddy()
+
+		fmt.Printf("正在为 %s 开启维护模式...\n", host)
+		if err := fc.Maintenance(host, true, opts); err != nil {
+			return fmt.Errorf("开启维护模式失败: %w", err)
+		}
+
+		fmt.Printf("✓ 维护模式已开启\n")
+		return nil
+	},
+}
+
+// maintenanceOffCmd 关闭维护模式子命令
+var maintenanceOffCmd = &cobra.Command{
+	Use:   "off <host>",
+	Short: "关闭维护模式",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		host := args[0]
+		fc := newFastCaddy()
+
+		fmt.Printf("正在为 %s 关闭维护模式...\n", host)
+		if err := fc.Maintenance(host, false, routes.MaintenanceOptions{}); err != nil {
+			return fmt.Errorf("关闭维护模式失败: %w", err)
+		}
+
+		fmt.Printf("✓ 维护模式已关闭\n")
+		return nil
+	},
+}
+
+func init() {
+	maintenanceOnCmd.Flags().IntVar(&maintenanceRetryAfter, "retry-after", 0, "Retry-After 响应头的秒数")
+	maintenanceOnCmd.Flags().StringVar(&maintenanceAllow, "allow", "", "允许绕过维护页面的 IP/CIDR 列表，用逗号分隔")
+	maintenanceOnCmd.Flags().StringVar(&maintenanceBodyFile, "body-file", "", "自定义维护页面 HTML 文件")
+
+	maintenanceCmd.AddCommand(maintenanceOnCmd)
+	maintenanceCmd.AddCommand(maintenanceOffCmd)
+	rootCmd.AddCommand(maintenanceCmd)
+}
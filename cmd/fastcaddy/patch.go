@@ -0,0 +1,94 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/youfun/fastcaddy/pkg/types"
+)
+
+var (
+	// patch 命令参数
+	patchFile   string
+	patchDryRun bool
+	patchPerOp  bool
+)
+
+// patchCmd 应用 JSON Patch 命令
+var patchCmd = &cobra.Command{
+	Use:   "patch",
+	Short: "对正在运行的配置应用 JSON Patch",
+	Long: `读取 JSON Patch (RFC 6902) 文件并应用到正在运行的配置。
+
+执行前先在本地完整预览和校验，任一操作失败 (路径不存在、test 不相等等) 时不修改配置，
+预览结果通过 /load 一次性提交；期间配置如被其他客户端修改，提交失败。
+--dry-run 只输出应用后的完整配置，不修改正在运行的配置。
+--per-op 将每个操作作为单独的 /config/ 请求发送，Caddy 拒绝其中某个请求时之前的操作不会撤销。
+
+示例:
+  fastcaddy patch -f changes.json --dry-run
+  fastcaddy patch -f changes.json
+
+changes.json:
+  [
+    {"op": "test", "path": "/apps/http/servers/srv0/listen/0", "value": ":443"},
+    {"op": "add", "path": "/apps/http/servers/srv0/protocols", "value": ["h1", "h2", "h3"]},
+    {"op": "remove", "path": "/apps/http/servers/srv0/routes/0"}
+  ]`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		var data []byte
+		var err error
+		if patchFile == "-" {
+			data, err = io.ReadAll(os.Stdin)
+		} else {
+			data, err = os.ReadFile(patchFile)
+		}
+		if err != nil {
+			return fmt.Errorf("读取补丁文件失败: %w", err)
+		}
+
+		patch, err := types.DecodePatch(data)
+		if err != nil {
+			return err
+		}
+
+		fc := newFastCaddy()
+		if patchDryRun {
+			preview, err := fc.PreviewPatch(patch)
+			if err != nil {
+				return fmt.Errorf("预览补丁失败: %w", err)
+			}
+			out, err := json.MarshalIndent(preview, "", "  ")
+			if err != nil {
+				return err
+			}
+			fmt.Println(string(out))
+			return nil
+		}
+
+		fmt.Printf("正在应用 %d 个操作...\n", len(patch))
+		apply := fc.ApplyPatch
+		if patchPerOp {
+			apply = fc.ApplyPatchOps
+		}
+		if err := apply(patch); err != nil {
+			return fmt.Errorf("应用补丁失败: %w", err)
+		}
+		for _, op := range patch {
+			fmt.Printf("✓ %s\n", op)
+		}
+		return nil
+	},
+}
+
+func init() {
+	patchCmd.Flags().StringVarP(&patchFile, "file", "f", "", "JSON Patch 文件路径，- 表示标准输入（必需）")
+	patchCmd.Flags().BoolVar(&patchDryRun, "dry-run", false, "只预览应用后的配置，不修改正在运行的配置")
+	patchCmd.Flags().BoolVar(&patchPerOp, "per-op", false, "逐个操作发送 /config/ 请求，而不是一次性提交 (非原子)")
+	patchCmd.MarkFlagRequired("file")
+
+	rootCmd.AddCommand(patchCmd)
+}
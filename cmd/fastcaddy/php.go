@@ -0,0 +1,59 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+	"github.com/youfun/fastcaddy/internal/utils"
+)
+
+var (
+	// add-php 命令参数
+	phpHost string
+	phpRoot string
+	phpFPM  string
+)
+
+// addPHPCmd 添加 PHP 站点命令
+var addPHPCmd = &cobra.Command{
+	Use:   "add-php",
+	Short: "添加 PHP (FastCGI) 站点",
+	Long: `为指定主机添加 PHP 站点，等价于 Caddyfile 中的 php_fastcgi 指令。
+
+PHP 脚本转发到 PHP-FPM，不存在的路径交给 index.php 处理，其余静态文件直接提供。
+
+示例:
+  fastcaddy add-php --host blog.example.com --root /var/www/blog --fpm /run/php/php8.2-fpm.sock
+  fastcaddy add-php --host legacy.example.com --root /srv/legacy --fpm 127.0.0.1:9000`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if !utils.ValidateHost(phpHost) {
+			return fmt.Errorf("无效的主机名: %s", phpHost)
+		}
+
+		root, err := filepath.Abs(phpRoot)
+		if err != nil {
+			return fmt.Errorf("无效的根目录: %w", err)
+		}
+
+		fc := newFastCaddy()
+
+		fmt.Printf("正在添加 PHP 站点: %s -> %s (FPM: %s)\n", phpHost, root, phpFPM)
+		if err := fc.AddPHPFastCGI(phpHost, root, phpFPM); err != nil {
+			return fmt.Errorf("添加 PHP 站点失败: %w", err)
+		}
+
+		fmt.Printf("✓ PHP 站点添加成功\n")
+		return nil
+	},
+}
+
+func init() {
+	addPHPCmd.Flags().StringVar(&phpHost, "host", "", "主机名（必需）")
+	addPHPCmd.Flags().StringVar(&phpRoot, "root", "", "站点根目录（必需）")
+	addPHPCmd.Flags().StringVar(&phpFPM, "fpm", "127.0.0.1:9000", "PHP-FPM 地址（TCP 地址或 Unix 套接字路径）")
+	addPHPCmd.MarkFlagRequired("host")
+	addPHPCmd.MarkFlagRequired("root")
+
+	rootCmd.AddCommand(addPHPCmd)
+}
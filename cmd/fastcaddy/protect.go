@@ -0,0 +1,128 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/youfun/fastcaddy/internal/routes"
+	"github.com/youfun/fastcaddy/internal/utils"
+)
+
+var (
+	// protect 命令参数
+	protectID          string
+	protectUsers       []string
+	protectRealm       string
+	protectAuthURL     string
+	protectAuthURI     string
+	protectCopyHeaders string
+)
+
+// protectCmd 认证保护命令
+var protectCmd = &cobra.Command{
+	Use:   "protect",
+	Short: "为路由添加认证保护",
+	Long: `为已存在的路由添加 HTTP 基本认证或转发认证（Authelia、oauth2-proxy 等）。
+
+示例:
+  fastcaddy protect basic --id admin.example.com --user alice:secret --user bob:pass
+  fastcaddy protect forward --id admin.example.com --to authelia:9091 --uri "/api/verify?rd=https://auth.example.com" --copy-headers Remote-User,Remote-Groups
+  fastcaddy protect remove --id admin.example.com`,
+}
+
+// protectBasicCmd HTTP 基本认证子命令
+var protectBasicCmd = &cobra.Command{
+	Use:   "basic",
+	Short: "启用 HTTP 基本认证",
+	Long:  `为路由启用 HTTP 基本认证，密码在本地使用 bcrypt 哈希后再发送到 Caddy。`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		users := make(map[string]string)
+		for _, user := range protectUsers {
+			username, password, ok := strings.Cut(user, ":")
+			if !ok || username == "" || password == "" {
+				return fmt.Errorf("无效的账户格式: %s (应为 用户名:密码)", user)
+			}
+			users[username] = password
+		}
+
+		fc := newFastCaddy()
+
+		fmt.Printf("正在为 %s 启用基本认证 (%d 个账户)...\n", protectID, len(users))
+		err := fc.Protect(protectID, routes.AuthConfig{
+			Users: users,
+			Realm: protectRealm,
+		})
+		if err != nil {
+			return fmt.Errorf("启用基本认证失败: %w", err)
+		}
+
+		fmt.Printf("✓ 基本认证已启用\n")
+		return nil
+	},
+}
+
+// protectForwardCmd 转发认证子命令
+var protectForwardCmd = &cobra.Command{
+	Use:   "forward",
+	Short: "启用转发认证",
+	Long:  `为路由启用转发认证，每个请求会先发送到认证服务进行校验。`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if !utils.ValidateURL(protectAuthURL) {
+			return fmt.Errorf("无效的认证服务地址: %s", protectAuthURL)
+		}
+
+		fc := newFastCaddy()
+
+		fmt.Printf("正在为 %s 启用转发认证: %s\n", protectID, protectAuthURL)
+		err := fc.Protect(protectID, routes.AuthConfig{
+			ForwardAuth: &routes.ForwardAuthConfig{
+				Upstream:    protectAuthURL,
+				URI:         protectAuthURI,
+				CopyHeaders: utils.SplitList(protectCopyHeaders),
+			},
+		})
+		if err != nil {
+			return fmt.Errorf("启用转发认证失败: %w", err)
+		}
+
+		fmt.Printf("✓ 转发认证已启用\n")
+		return nil
+	},
+}
+
+// protectRemoveCmd 移除认证子命令
+var protectRemoveCmd = &cobra.Command{
+	Use:   "remove",
+	Short: "移除认证保护",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		fc := newFastCaddy()
+
+		fmt.Printf("正在移除 %s 的认证保护...\n", protectID)
+		if err := fc.Unprotect(protectID); err != nil {
+			return fmt.Errorf("移除认证保护失败: %w", err)
+		}
+
+		fmt.Printf("✓ 认证保护已移除\n")
+		return nil
+	},
+}
+
+func init() {
+	protectCmd.PersistentFlags().StringVar(&protectID, "id", "", "路由 ID（必需）")
+	protectCmd.MarkPersistentFlagRequired("id")
+
+	protectBasicCmd.Flags().StringArrayVar(&protectUsers, "user", nil, "账户，格式为 用户名:密码，可重复指定（必需）")
+	protectBasicCmd.Flags().StringVar(&protectRealm, "realm", "", "认证域")
+	protectBasicCmd.MarkFlagRequired("user")
+
+	protectForwardCmd.Flags().StringVar(&protectAuthURL, "to", "", "认证服务地址（必需）")
+	protectForwardCmd.Flags().StringVar(&protectAuthURI, "uri", "/", "认证请求 URI")
+	protectForwardCmd.Flags().StringVar(&protectCopyHeaders, "copy-headers", "", "认证通过后复制的响应头，用逗号分隔")
+	protectForwardCmd.MarkFlagRequired("to")
+
+	protectCmd.AddCommand(protectBasicCmd)
+	protectCmd.AddCommand(protectForwardCmd)
+	protectCmd.AddCommand(protectRemoveCmd)
+	rootCmd.AddCommand(protectCmd)
+}
@@ -0,0 +1,85 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/youfun/fastcaddy/internal/routes"
+)
+
+var (
+	// add-redirect 命令参数
+	redirectFrom string
+	redirectTo   string
+	redirectCode int
+	redirectCSV  string
+)
+
+// addRedirectCmd 添加重定向命令
+var addRedirectCmd = &cobra.Command{
+	Use:   "add-redirect",
+	Short: "添加重定向",
+	Long: `添加主机或路径重定向，支持从 CSV 文件批量导入。
+
+目标地址支持占位符简写: {uri}, {path}, {query}, {host}
+CSV 文件每行格式为 from,to[,code]，可包含 "from,to,code" 表头。
+
+示例:
+  fastcaddy add-redirect --from www.example.com --to https://example.com{uri} --code 308
+  fastcaddy add-redirect --from example.com/old-blog/* --to https://blog.example.com{uri}
+  fastcaddy add-redirect --csv redirects.csv`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		var redirects []routes.Redirect
+
+		if redirectCSV != "" {
+			file, err := os.Open(redirectCSV)
+			if err != nil {
+				return fmt.Errorf("打开 CSV 文件失败: %w", err)
+			}
+			defer file.Close()
+
+			redirects, err = routes.ParseRedirectsCSV(file)
+			if err != nil {
+				return err
+			}
+		} else {
+			if redirectFrom == "" || redirectTo == "" {
+				return fmt.Errorf("必须指定 --from 和 --to 参数，或使用 --csv 批量导入")
+			}
+			redirects = append(redirects, routes.Redirect{
+				From: redirectFrom,
+				To:   redirectTo,
+				Code: redirectCode,
+			})
+		}
+
+		// 提前校验所有规则，避免部分写入
+		for _, redirect := range redirects {
+			if _, err := routes.RedirectRoute(redirect); err != nil {
+				return fmt.Errorf("重定向 %s 无效: %w", redirect.From, err)
+			}
+		}
+
+		fc := newFastCaddy()
+
+		for _, redirect := range redirects {
+			fmt.Printf("正在添加重定向: %s -> %s\n", redirect.From, redirect.To)
+			if err := fc.AddRedirect(redirect.From, redirect.To, redirect.Code); err != nil {
+				return fmt.Errorf("添加重定向失败: %w", err)
+			}
+		}
+
+		fmt.Printf("✓ 已添加 %d 条重定向\n", len(redirects))
+		return nil
+	},
+}
+
+func init() {
+	addRedirectCmd.Flags().StringVar(&redirectFrom, "from", "", "来源主机名或主机名加路径")
+	addRedirectCmd.Flags().StringVar(&redirectTo, "to", "", "目标地址")
+	addRedirectCmd.Flags().IntVar(&redirectCode, "code", 302, "重定向状态码（301, 302, 307, 308）")
+	addRedirectCmd.Flags().StringVar(&redirectCSV, "csv", "", "从 CSV 文件批量导入重定向")
+
+	rootCmd.AddCommand(addRedirectCmd)
+}
@@ -0,0 +1,180 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/youfun/fastcaddy/internal/routes"
+	"github.com/youfun/fastcaddy/internal/utils"
+)
+
+var (
+	// restrict 命令参数
+	restrictID    string
+	restrictAllow string
+	restrictDeny  string
+	restrictSave  string
+)
+
+// restrictCmd IP 访问限制命令
+var restrictCmd = &cobra.Command{
+	Use:   "restrict",
+	Short: "限制路由的访问来源 IP",
+	Long: `为已存在的路由设置 IP 允许列表和拒绝列表，不满足条件的请求返回 403。
+地址可以是 IP、CIDR，或以 @ 开头引用命名 IP 列表 (见 iplist 命令)。
+
+示例:
+  fastcaddy restrict set --id admin.example.com --allow 10.8.0.0/16,192.168.1.0/24
+  fastcaddy restrict set --id admin.example.com --allow 10.8.0.0/16,10.9.0.0/16 --save-as vpn
+  fastcaddy restrict set --id grafana.example.com --allow @vpn,127.0.0.1
+  fastcaddy restrict set --id api.example.com --deny 203.0.113.0/24
+  fastcaddy restrict remove --id admin.example.com`,
+}
+
+// restrictSetCmd 设置 IP 访问限制子命令
+var restrictSetCmd = &cobra.Command{
+	Use:   "set",
+	Short: "设置 IP 访问限制",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		allow := utils.SplitList(restrictAllow)
+		deny := utils.SplitList(restrictDeny)
+
+		fc := newFastCaddy()
+
+		fmt.Printf("正在为 %s 设置 IP 访问限制...\n", restrictID)
+		opts := routes.IPRestrictionOptions{Allow: allow, Deny: deny, SaveAs: restrictSave}
+		if err := fc.RestrictIPsWithOptions(restrictID, opts); err != nil {
+			return fmt.Errorf("设置 IP 访问限制失败: %w", err)
+		}
+
+		fmt.Printf("✓ IP 访问限制已设置\n")
+		return nil
+	},
+}
+
+// restrictRemoveCmd 移除 IP 访问限制子命令
+var restrictRemoveCmd = &cobra.Command{
+	Use:   "remove",
+	Short: "移除 IP 访问限制",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		fc := newFastCaddy()
+
+		fmt.Printf("正在移除 %s 的 IP 访问限制...\n", restrictID)
+		if err := fc.Unrestrict(restrictID); err != nil {
+			return fmt.Errorf("移除 IP 访问限制失败: %w", err)
+		}
+
+		fmt.Printf("✓ IP 访问限制已移除\n")
+		return nil
+	},
+}
+
+// iplistCmd 命名 IP 列表命令
+var iplistCmd = &cobra.Command{
+	Use:   "iplist",
+	Short: "管理可复用的命名 IP 列表",
+	Long: `命名 IP 列表通过 restrict set --save-as 创建，可以在 restrict 命令中通过 @名称 引用。
+列表保存在引用它的访问限制中，更新列表后所有引用同步更新；
+最后一个引用被移除时列表随之删除。
+
+示例:
+  fastcaddy restrict set --id admin.example.com --allow 10.8.0.0/16 --save-as vpn
+  fastcaddy iplist set vpn 10.8.0.0/16,10.9.0.0/16
+  fastcaddy iplist show vpn
+  fastcaddy iplist list
+  fastcaddy iplist delete vpn`,
+}
+
+// iplistSetCmd 更新命名 IP 列表子命令
+var iplistSetCmd = &cobra.Command{
+	Use:   "set <name> <ranges>",
+	Short: "更新命名 IP 列表",
+	Args:  cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		name, ranges := args[0], utils.SplitList(args[1])
+		fc := newFastCaddy()
+
+		fmt.Printf("正在保存 IP 列表 %s (%d 个地址)...\n", name, len(ranges))
+		if err := fc.SetIPList(name, ranges); err != nil {
+			return fmt.Errorf("保存 IP 列表失败: %w", err)
+		}
+
+		fmt.Printf("✓ IP 列表已保存\n")
+		return nil
+	},
+}
+
+// iplistShowCmd 查看命名 IP 列表子命令
+var iplistShowCmd = &cobra.Command{
+	Use:   "show <name>",
+	Short: "查看命名 IP 列表",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		fc := newFastCaddy()
+
+		ranges, err := fc.Routes.GetIPList(args[0])
+		if err != nil {
+			return err
+		}
+		fmt.Println(strings.Join(ranges, "\n"))
+		return nil
+	},
+}
+
+// iplistListCmd 列出命名 IP 列表子命令
+var iplistListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "列出所有命名 IP 列表",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		fc := newFastCaddy()
+
+		names, err := fc.Routes.IPLists()
+		if err != nil {
+			return err
+		}
+		if len(names) == 0 {
+			fmt.Println("没有命名 IP 列表")
+			return nil
+		}
+		fmt.Println(strings.Join(names, "\n"))
+		return nil
+	},
+}
+
+// iplistDeleteCmd 删除命名 IP 列表子命令
+var iplistDeleteCmd = &cobra.Command{
+	Use:   "delete <name>",
+	Short: "删除命名 IP 列表 (引用处保留当前地址)",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		fc := newFastCaddy()
+
+		fmt.Printf("正在删除 IP 列表 %s...\n", args[0])
+		if err := fc.Routes.DeleteIPList(args[0]); err != nil {
+			return fmt.Errorf("删除 IP 列表失败: %w", err)
+		}
+
+		fmt.Printf("✓ IP 列表已删除\n")
+		return nil
+	},
+}
+
+func init() {
+	restrictCmd.PersistentFlags().StringVar(&restrictID, "id", "", "路由 ID（必需）")
+	restrictCmd.MarkPersistentFlagRequired("id")
+
+	restrictSetCmd.Flags().StringVar(&restrictAllow, "allow", "", "允许访问的 IP/CIDR 或 @列表名，用逗号分隔")
+	restrictSetCmd.Flags().StringVar(&restrictDeny, "deny", "", "拒绝访问的 IP/CIDR 或 @列表名，用逗号分隔")
+	restrictSetCmd.Flags().StringVar(&restrictSave, "save-as", "", "将 --allow 中直接指定的地址保存为命名 IP 列表")
+
+	restrictCmd.AddCommand(restrictSetCmd)
+	restrictCmd.AddCommand(restrictRemoveCmd)
+	rootCmd.AddCommand(restrictCmd)
+
+	iplistCmd.AddCommand(iplistSetCmd)
+	iplistCmd.AddCommand(iplistShowCmd)
+	iplistCmd.AddCommand(iplistListCmd)
+	iplistCmd.AddCommand(iplistDeleteCmd)
+	rootCmd.AddCommand(iplistCmd)
+}
@@ -0,0 +1,85 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+	"github.com/youfun/fastcaddy/internal/routes"
+	"github.com/youfun/fastcaddy/internal/utils"
+)
+
+var (
+	// add-static 命令参数
+	staticHost          string
+	staticRoot          string
+	staticBrowse        bool
+	staticIndex         string
+	staticPrecompressed string
+	staticHide          string
+	staticHideDotfiles  bool
+	staticSPA           bool
+)
+
+// addStaticCmd 添加静态文件服务命令
+var addStaticCmd = &cobra.Command{
+	Use:   "add-static",
+	Short: "添加静态文件服务",
+	Long: `为指定主机添加静态文件服务，适用于前端静态资源和单页应用。
+
+示例:
+  fastcaddy add-static --host www.example.com --root /srv/www
+  fastcaddy add-static --host app.example.com --root /srv/app/dist --spa --precompressed br,gzip
+  fastcaddy add-static --host files.example.com --root /srv/files --browse --hide-dotfiles`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if !utils.ValidateHost(staticHost) {
+			return fmt.Errorf("无效的主机名: %s", staticHost)
+		}
+
+		// Caddy 运行时解析相对路径的目录可能与当前目录不同，统一使用绝对路径
+		root, err := filepath.Abs(staticRoot)
+		if err != nil {
+			return fmt.Errorf("无效的根目录: %w", err)
+		}
+
+		opts := routes.FileServerOptions{
+			Browse:        staticBrowse,
+			IndexNames:    utils.SplitList(staticIndex),
+			Precompressed: utils.SplitList(staticPrecompressed),
+			Hide:          utils.SplitList(staticHide),
+			HideDotfiles:  staticHideDotfiles,
+			SPA:           staticSPA,
+		}
+
+		for _, format := range opts.Precompressed {
+			if !utils.StringSliceContains([]string{"br", "gzip", "zstd"}, format) {
+				return fmt.Errorf("不支持的预压缩格式: %s (可选: br, gzip, zstd)", format)
+			}
+		}
+
+		fc := newFastCaddy()
+
+		fmt.Printf("正在添加静态文件服务: %s -> %s\n", staticHost, root)
+		if err := fc.AddFileServer(staticHost, root, opts); err != nil {
+			return fmt.Errorf("添加静态文件服务失败: %w", err)
+		}
+
+		fmt.Printf("✓ 静态文件服务添加成功\n")
+		return nil
+	},
+}
+
+func init() {
+	addStaticCmd.Flags().StringVar(&staticHost, "host", "", "主机名（必需）")
+	addStaticCmd.Flags().StringVar(&staticRoot, "root", "", "站点根目录（必需）")
+	addStaticCmd.Flags().BoolVar(&staticBrowse, "browse", false, "启用目录浏览")
+	addStaticCmd.Flags().StringVar(&staticIndex, "index", "", "索引文件名列表，用逗号分隔")
+	addStaticCmd.Flags().StringVar(&staticPrecompressed, "precompressed", "", "预压缩文件格式，按优先顺序用逗号分隔（br,zstd,gzip）")
+	addStaticCmd.Flags().StringVar(&staticHide, "hide", "", "隐藏的文件模式列表，用逗号分隔")
+	addStaticCmd.Flags().BoolVar(&staticHideDotfiles, "hide-dotfiles", false, "隐藏以 '.' 开头的文件和目录")
+	addStaticCmd.Flags().BoolVar(&staticSPA, "spa", false, "单页应用模式：找不到文件时回退到 /index.html")
+	addStaticCmd.MarkFlagRequired("host")
+	addStaticCmd.MarkFlagRequired("root")
+
+	rootCmd.AddCommand(addStaticCmd)
+}
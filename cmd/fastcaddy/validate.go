@@ -0,0 +1,67 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/youfun/fastcaddy/internal/config"
+)
+
+var (
+	// validate 命令参数
+	validateFile string
+)
+
+// validateCmd 校验配置文件命令
+var validateCmd = &cobra.Command{
+	Use:   "validate",
+	Short: "校验 JSON 配置文件",
+	Long: `在本地校验 Caddy JSON 配置文件的结构，不需要连接 Caddy。
+
+检查 @id 是否唯一、主机名格式、上游和监听地址、端口范围、处理器名称以及反向代理的上游是否为空。
+未知的处理器 (可能来自插件) 和包含 '/' 的 @id 只作为警告输出。
+fastcaddy 在每次修改配置前也会自动校验要写入的部分，可以使用 --skip-validation 跳过。
+
+示例:
+  fastcaddy validate -f config.json
+  fastcaddy export --format json | fastcaddy validate -f -`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		var data []byte
+		var err error
+		if validateFile == "-" {
+			data, err = io.ReadAll(os.Stdin)
+		} else {
+			data, err = os.ReadFile(validateFile)
+		}
+		if err != nil {
+			return fmt.Errorf("读取配置文件失败: %w", err)
+		}
+
+		issues, err := config.CheckConfig(data)
+		if err != nil {
+			return err
+		}
+		for _, e := range issues {
+			if e.Warning {
+				fmt.Printf("警告: %s\n", e)
+			} else {
+				fmt.Printf("✗ %s\n", e)
+			}
+		}
+		if errs := issues.Errors(); len(errs) > 0 {
+			return fmt.Errorf("配置校验失败: 发现 %d 个问题", len(errs))
+		}
+
+		fmt.Printf("✓ 配置校验通过\n")
+		return nil
+	},
+}
+
+func init() {
+	validateCmd.Flags().StringVarP(&validateFile, "file", "f", "", "JSON 配置文件路径，- 表示标准输入（必需）")
+	validateCmd.MarkFlagRequired("file")
+
+	rootCmd.AddCommand(validateCmd)
+}
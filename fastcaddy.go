@@ -1,35 +1,75 @@
 package fastcaddy
 
 import (
+	"encoding/json"
+	"fmt"
+
 	"github.com/youfun/fastcaddy/internal/api"
 	"github.com/youfun/fastcaddy/internal/config"
+	"github.com/youfun/fastcaddy/internal/export"
+	"github.com/youfun/fastcaddy/internal/logging"
 	"github.com/youfun/fastcaddy/internal/routes"
 	"github.com/youfun/fastcaddy/internal/tls"
 	"github.com/youfun/fastcaddy/internal/utils"
+	"github.com/youfun/fastcaddy/pkg/types"
 )
 
 // FastCaddy 主要客户端 - 提供 Caddy 配置管理的统一接口
 // 这是主要的入口点，整合了所有功能模块
 type FastCaddy struct {
-	API    *api.Client      // API 客户端
-	Config *config.Manager  // 配置管理器  
-	TLS    *tls.Manager     // TLS 管理器
-	Routes *routes.Manager  // 路由管理器
+	API     *api.Client      // API 客户端
+	Config  *config.Manager  // 配置管理器
+	TLS     *tls.Manager     // TLS 管理器
+	Routes  *routes.Manager  // 路由管理器
+	Logging *logging.Manager // 日志管理器
 }
 
 // New 创建新的 FastCaddy 客户端实例
 func New() *FastCaddy {
 	return &FastCaddy{
-		API:    api.NewClient(),
-		Config: config.NewManager(),
-		TLS:    tls.NewManager(),
-		Routes: routes.NewManager(),
+		API:     api.NewClient(),
+		Config:  config.NewManager(),
+		TLS:     tls.NewManager(),
+		Routes:  routes.NewManager(),
+		Logging: logging.NewManager(),
+	}
+}
+
+// NewWithClient 使用指定的 API 客户端创建 FastCaddy 实例
+// 所有管理器共用同一个客户端 (如连接其他地址的 Caddy，或事务中的暂存配置)
+func NewWithClient(client *api.Client) *FastCaddy {
+	return &FastCaddy{
+		API:     client,
+		Config:  config.NewManagerWithClient(client),
+		TLS:     tls.NewManagerWithClient(client),
+		Routes:  routes.NewManagerWithClient(client),
+		Logging: logging.NewManagerWithClient(client),
 	}
 }
 
 // SetupCaddy 设置 Caddy 基本配置 - 对应 Python 的 setup_caddy 函数
-// 这是初始化 Caddy 配置的主要函数，包括 SSL 配置和 HTTP 应用骨架
+// 这是初始化 Caddy 配置的主要函数，包括 SSL 配置和 HTTP 应用骨架；
+// 所有步骤在同一事务中执行，任一步骤失败时正在运行的配置保持不变
 func (fc *FastCaddy) SetupCaddy(cfToken, serverName string, local bool, installTrust *bool) error {
+	return fc.SetupCaddyWithOptions(cfToken, serverName, local, installTrust, nil)
+}
+
+// SetupCaddyWithOptions 与 SetupCaddy 相同，并在同一事务中应用服务器选项
+// options 为 nil 时不修改服务器选项
+func (fc *FastCaddy) SetupCaddyWithOptions(cfToken, serverName string, local bool, installTrust *bool, options *types.HTTPServer) error {
+	return fc.Transaction(func(tx *Tx) error {
+		if err := tx.setup(cfToken, serverName, local, installTrust); err != nil {
+			return err
+		}
+		if options == nil {
+			return nil
+		}
+		return tx.ConfigureServer(serverName, *options)
+	})
+}
+
+// setup 依次写入 TLS、PKI 和 HTTP 服务器配置
+func (fc *FastCaddy) setup(cfToken, serverName string, local bool, installTrust *bool) error {
 	// 根据环境设置 TLS 配置
 	if local {
 		// 本地开发环境：使用内部证书
@@ -60,24 +100,211 @@ func (fc *FastCaddy) SetupCaddy(cfToken, serverName string, local bool, installT
 	return fc.Routes.InitRoutes(serverName, 1)
 }
 
+// ConfigureServer 配置服务器协议选项 - 便利方法
+// 更新 HTTP/3、h2c、监听器包装器、受信任代理和超时等选项
+func (fc *FastCaddy) ConfigureServer(serverName string, options types.HTTPServer) error {
+	if serverName == "" {
+		serverName = "srv0" // 默认服务器名
+	}
+	return fc.Routes.ConfigureServer(serverName, options)
+}
+
 // AddReverseProxy 添加反向代理 - 便利方法
 // 创建从指定主机到目标 URL 的反向代理路由
 func (fc *FastCaddy) AddReverseProxy(fromHost, toURL string) error {
 	return fc.Routes.AddReverseProxy(fromHost, toURL)
 }
 
+// AddReverseProxyWithOptions 添加带选项的反向代理 - 便利方法
+// 支持安全响应头预设和请求/响应头操作
+func (fc *FastCaddy) AddReverseProxyWithOptions(fromHost, toURL string, opts routes.ProxyOptions) error {
+	return fc.Routes.AddReverseProxyWithOptions(fromHost, toURL, opts)
+}
+
+// AddFileServer 添加静态文件服务 - 便利方法
+// 为指定主机提供静态文件，支持目录浏览、预压缩和 SPA 回退
+func (fc *FastCaddy) AddFileServer(host, root string, opts routes.FileServerOptions) error {
+	return fc.Routes.AddFileServer(host, root, opts)
+}
+
+// AddPHPFastCGI 添加 PHP 站点 - 便利方法
+// 将 PHP 脚本转发到 PHP-FPM，其余静态文件直接提供
+func (fc *FastCaddy) AddPHPFastCGI(host, root, fpmAddr string) error {
+	return fc.Routes.AddPHPFastCGI(host, root, fpmAddr)
+}
+
+// AddRedirect 添加重定向 - 便利方法
+// 将来源主机（或主机加路径）重定向到目标地址
+func (fc *FastCaddy) AddRedirect(from, to string, code int) error {
+	return fc.Routes.AddRedirect(from, to, code)
+}
+
+// AddStaticResponse 添加静态响应 - 便利方法
+// 为指定主机返回固定的状态码、内容和响应头
+func (fc *FastCaddy) AddStaticResponse(host string, opts routes.StaticResponseOptions) error {
+	return fc.Routes.AddStaticResponse(host, opts)
+}
+
 // AddWildcardRoute 添加通配符路由 - 便利方法
 // 为指定域名创建通配符子域名路由
 func (fc *FastCaddy) AddWildcardRoute(domain string) error {
 	return fc.Routes.AddWildcardRoute(domain)
 }
 
+// Domain 返回通配符域名管理对象 - 便利方法
+// 用于列出、添加、更新和删除 *.<域名> 下的子域名
+func (fc *FastCaddy) Domain(name string) *routes.Domain {
+	return fc.Routes.Domain(name)
+}
+
 // AddSubReverseProxy 添加子域名反向代理 - 便利方法
 // 为通配符域名下的特定子域名添加反向代理
 func (fc *FastCaddy) AddSubReverseProxy(domain, subdomain string, ports interface{}, host string) error {
 	return fc.Routes.AddSubReverseProxyWithPorts(domain, subdomain, ports, host)
 }
 
+// AddSubReverseProxyWithOptions 添加带选项的子域名反向代理 - 便利方法
+func (fc *FastCaddy) AddSubReverseProxyWithOptions(domain, subdomain string, ports []string, host string, opts routes.ProxyOptions) error {
+	return fc.Routes.AddSubReverseProxyWithOptions(domain, subdomain, ports, host, opts)
+}
+
+// Protect 为路由添加认证保护 - 便利方法
+// 支持 HTTP 基本认证和转发认证
+func (fc *FastCaddy) Protect(id string, cfg routes.AuthConfig) error {
+	return fc.Routes.Protect(id, cfg)
+}
+
+// Unprotect 移除路由的认证保护 - 便利方法
+func (fc *FastCaddy) Unprotect(id string) error {
+	return fc.Routes.Unprotect(id)
+}
+
+// SetCORS 为路由设置跨域策略 - 便利方法
+// 添加 OPTIONS 预检路由和 CORS 响应头，重复调用会更新现有策略
+func (fc *FastCaddy) SetCORS(id string, policy routes.CORSPolicy) error {
+	return fc.Routes.SetCORS(id, policy)
+}
+
+// RemoveCORS 移除路由的跨域策略 - 便利方法
+func (fc *FastCaddy) RemoveCORS(id string) error {
+	return fc.Routes.RemoveCORS(id)
+}
+
+// RestrictIPs 限制路由的访问来源 IP - 便利方法
+// allow 和 deny 支持 IP、CIDR 和 "@名称" 形式的命名 IP 列表引用
+func (fc *FastCaddy) RestrictIPs(id string, allow, deny []string) error {
+	return fc.Routes.RestrictIPs(id, allow, deny)
+}
+
+// RestrictIPsWithOptions 限制路由的访问来源 IP，支持保存命名 IP 列表 - 便利方法
+func (fc *FastCaddy) RestrictIPsWithOptions(id string, opts routes.IPRestrictionOptions) error {
+	return fc.Routes.RestrictIPsWithOptions(id, opts)
+}
+
+// Unrestrict 移除路由的 IP 访问限制 - 便利方法
+func (fc *FastCaddy) Unrestrict(id string) error {
+	return fc.Routes.Unrestrict(id)
+}
+
+// SetIPList 更新命名 IP 列表 - 便利方法
+// 所有引用该列表的访问限制会同步更新
+func (fc *FastCaddy) SetIPList(name string, ranges []string) error {
+	return fc.Routes.SetIPList(name, ranges)
+}
+
+// EnableCompression 为路由启用响应压缩 - 便利方法
+func (fc *FastCaddy) EnableCompression(id string) error {
+	return fc.Routes.EnableCompression(id)
+}
+
+// SetErrorPages 设置自定义错误页面 - 便利方法
+// pages 为状态码到页面的映射，状态码 0 表示默认页面
+func (fc *FastCaddy) SetErrorPages(serverName string, pages map[int]string, opts routes.ErrorPageOptions) error {
+	if serverName == "" {
+		serverName = "srv0" // 默认服务器名
+	}
+	return fc.Routes.SetErrorPages(serverName, pages, opts)
+}
+
+// RemoveErrorPages 移除自定义错误页面 - 便利方法
+func (fc *FastCaddy) RemoveErrorPages(host string) error {
+	return fc.Routes.RemoveErrorPages(host)
+}
+
+// Maintenance 切换主机的维护模式
+// on 为 true 时插入返回 503 的高优先级路由，为 false 时移除该路由；原有路由保持不变
+func (fc *FastCaddy) Maintenance(host string, on bool, opts routes.MaintenanceOptions) error {
+	if on {
+		return fc.Routes.EnableMaintenance(host, opts)
+	}
+	return fc.Routes.DisableMaintenance(host)
+}
+
+// Canary 配置金丝雀发布 - 便利方法
+// 将 percent% 的流量分配到新的上游
+func (fc *FastCaddy) Canary(id string, newUpstreams []string, percent int) error {
+	return fc.Routes.Canary(id, newUpstreams, percent)
+}
+
+// PromoteCanary 全量切换到金丝雀上游 - 便利方法
+func (fc *FastCaddy) PromoteCanary(id string) error {
+	return fc.Routes.PromoteCanary(id)
+}
+
+// AbortCanary 回滚金丝雀发布 - 便利方法
+func (fc *FastCaddy) AbortCanary(id string) error {
+	return fc.Routes.AbortCanary(id)
+}
+
+// EnableAccessLog 为主机启用访问日志 - 便利方法
+func (fc *FastCaddy) EnableAccessLog(serverName, host string, opts logging.AccessLogOptions) error {
+	if serverName == "" {
+		serverName = "srv0" // 默认服务器名
+	}
+	return fc.Logging.EnableAccessLog(serverName, host, opts)
+}
+
+// DisableAccessLog 关闭主机的访问日志 - 便利方法
+func (fc *FastCaddy) DisableAccessLog(serverName, host string) error {
+	if serverName == "" {
+		serverName = "srv0" // 默认服务器名
+	}
+	return fc.Logging.DisableAccessLog(serverName, host)
+}
+
+// ImportCaddyfile 导入 Caddyfile - 便利方法
+// 通过 /adapt 转换为 JSON，为路由分配 @id 后合并到正在运行的配置或整体替换；
+// 合并在事务中完成，任一步骤失败时不修改配置
+func (fc *FastCaddy) ImportCaddyfile(caddyfile []byte, opts routes.ImportOptions) (*routes.ImportResult, []api.AdaptWarning, error) {
+	adapted, err := fc.API.Adapt(caddyfile, "caddyfile")
+	if err != nil {
+		return nil, nil, err
+	}
+	var result *routes.ImportResult
+	err = fc.Transaction(func(tx *Tx) error {
+		result, err = tx.Routes.ImportConfig(adapted.Result, opts)
+		return err
+	})
+	if err != nil {
+		return nil, adapted.Warnings, err
+	}
+	return result, adapted.Warnings, nil
+}
+
+// ExportConfig 导出正在运行的配置 - 便利方法
+// format 可以是 "caddyfile" (近似转换，无法表达的部分输出为注释)、"json" 或 "yaml"
+func (fc *FastCaddy) ExportConfig(format string) ([]byte, error) {
+	cfg, err := fc.API.GetConfig("/")
+	if err != nil {
+		return nil, fmt.Errorf("获取配置失败: %w", err)
+	}
+	data, err := json.Marshal(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return export.Export(data, format)
+}
+
 // DeleteRoute 删除路由 - 便利方法
 // 通过路由 ID 删除特定路由
 func (fc *FastCaddy) DeleteRoute(id string) error {
@@ -102,4 +329,32 @@ func (fc *FastCaddy) GetConfig(path string) (map[string]interface{}, error) {
 // PutConfig 设置配置 - 便利方法
 func (fc *FastCaddy) PutConfig(data interface{}, path, method string) error {
 	return fc.API.PutConfig(data, path, method)
+}
+
+// PreviewPatch 预览 JSON Patch 应用后的完整配置，不修改正在运行的配置 - 便利方法
+func (fc *FastCaddy) PreviewPatch(patch types.JSONPatch) (json.RawMessage, error) {
+	return fc.API.PreviewPatch(patch)
+}
+
+// ApplyPatch 将 JSON Patch 应用到正在运行的配置 - 便利方法
+// 执行前先在本地完整预览，预览结果通过 /load 一次性提交，任一操作失败时不修改配置
+func (fc *FastCaddy) ApplyPatch(patch types.JSONPatch) error {
+	return fc.API.ApplyPatch(patch)
+}
+
+// ApplyPatchOps 将 JSON Patch 逐个操作发送到 /config/ - 便利方法
+// 不整体替换配置，但请求之间不是原子的
+func (fc *FastCaddy) ApplyPatchOps(patch types.JSONPatch) error {
+	return fc.API.ApplyPatchOps(patch)
+}
+
+// Get 获取指定路径的配置并解析为类型 T - 便利方法
+// 例如 fastcaddy.Get[types.CaddyConfig](fc, "/")、fastcaddy.Get[types.HTTPServer](fc, "/apps/http/servers/srv0")
+func Get[T any](fc *FastCaddy, path string) (T, error) {
+	return api.Get[T](fc.API, path)
+}
+
+// Put 将类型化的配置写入指定路径 - 便利方法
+func Put[T any](fc *FastCaddy, path string, value T, method string) error {
+	return api.Put(fc.API, path, value, method)
 }
\ No newline at end of file
@@ -0,0 +1,104 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// AdaptWarning 配置适配器返回的警告
+type AdaptWarning struct {
+	File      string `json:"file,omitempty"`
+	Line      int    `json:"line,omitempty"`
+	Directive string `json:"directive,omitempty"`
+	Message   string `json:"message,omitempty"`
+}
+
+// String 格式化警告信息
+func (w AdaptWarning) String() string {
+	if w.File != "" {
+		return fmt.Sprintf("%s:%d: %s", w.File, w.Line, w.Message)
+	}
+	return w.Message
+}
+
+// AdaptResult 配置适配结果
+type AdaptResult struct {
+	Result   json.RawMessage `json:"result"`   // 转换后的 JSON 配置
+	Warnings []AdaptWarning  `json:"warnings"` // 转换过程中的警告
+}
+
+// Adapt 使用 Caddy 的 /adapt 接口将其他格式的配置转换为 JSON
+// adapter 为适配器名称 (如 "caddyfile")，不会修改正在运行的配置
+func (c *Client) Adapt(body []byte, adapter string) (*AdaptResult, error) {
+	if adapter == "" {
+		adapter = "caddyfile"
+	}
+
+	req, err := http.NewRequest("POST", c.BaseURL+"/adapt", bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("创建 HTTP 请求失败: %w", err)
+	}
+	req.Header.Set("Content-Type", "text/"+adapter)
+
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("发送 HTTP 请求失败: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("转换配置失败: %w", responseError(resp))
+	}
+
+	var result AdaptResult
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("解析响应 JSON 失败: %w", err)
+	}
+	return &result, nil
+}
+
+// ErrConfigChanged 正在运行的配置在读取后已被修改 (If-Match 不匹配)
+var ErrConfigChanged = errors.New("配置在读取后已被其他客户端修改")
+
+// Load 使用 /load 接口整体替换正在运行的配置
+func (c *Client) Load(config interface{}) error {
+	return c.LoadIfMatch(config, "")
+}
+
+// LoadIfMatch 与 Load 相同，但只在正在运行的配置与 etag (GetConfigWithEtag 返回) 一致时替换
+// 配置已被修改时返回 ErrConfigChanged；etag 为空时不检查 (需要 Caddy 2.6 及以上版本)
+func (c *Client) LoadIfMatch(config interface{}, etag string) error {
+	if err := c.validate(config, "/"); err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(config)
+	if err != nil {
+		return fmt.Errorf("序列化请求数据失败: %w", err)
+	}
+	req, err := http.NewRequest("POST", c.BaseURL+"/load", bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("创建 HTTP 请求失败: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if etag != "" {
+		req.Header.Set("If-Match", etag)
+	}
+
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("发送 HTTP 请求失败: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusPreconditionFailed {
+		return ErrConfigChanged
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return responseError(resp)
+	}
+	return nil
+}
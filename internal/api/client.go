@@ -8,12 +8,15 @@ import (
 	"net/http"
 	"strings"
 	"time"
+
+	"github.com/youfun/fastcaddy/pkg/types"
 )
 
 // Client Caddy API 客户端 - 封装与 Caddy REST API 的交互
 type Client struct {
-	BaseURL    string       // Caddy API 基础 URL (默认: http://localhost:2019)
-	HTTPClient *http.Client // HTTP 客户端
+	BaseURL        string       // Caddy API 基础 URL (默认: http://localhost:2019)
+	HTTPClient     *http.Client // HTTP 客户端
+	SkipValidation bool         // 是否跳过写入前的本地配置校验 (使用未知插件模块时可设置)
 }
 
 // NewClient 创建新的 Caddy API 客户端
@@ -54,44 +57,82 @@ func (c *Client) GetConfigURL(path string) string {
 
 // GetByID 通过 ID 获取配置 - 对应 Python 的 gid(path) 函数
 func (c *Client) GetByID(path string) (map[string]interface{}, error) {
+	var result map[string]interface{}
+	if err := c.GetByIDInto(path, &result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// GetByIDInto 通过 ID 获取配置并解析到指定的值中
+// 适用于数组、字符串等非对象类型的配置，或直接解析为类型化结构
+func (c *Client) GetByIDInto(path string, v interface{}) error {
 	url := c.GetIDURL(path)
 	resp, err := c.HTTPClient.Get(url)
 	if err != nil {
-		return nil, fmt.Errorf("获取 ID 配置失败: %w", err)
+		return fmt.Errorf("获取 ID 配置失败: %w", err)
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("获取 ID 配置失败, 状态码: %d", resp.StatusCode)
+		return fmt.Errorf("获取 ID 配置失败, 状态码: %d", resp.StatusCode)
 	}
 
-	var result map[string]interface{}
-	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
-		return nil, fmt.Errorf("解析响应 JSON 失败: %w", err)
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		return fmt.Errorf("解析响应 JSON 失败: %w", err)
 	}
 
-	return result, nil
+	return nil
 }
 
 // GetConfig 获取指定路径的配置 - 对应 Python 的 gcfg(path, method) 函数
 func (c *Client) GetConfig(path string) (map[string]interface{}, error) {
+	var result map[string]interface{}
+	if err := c.GetConfigInto(path, &result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// GetConfigInto 获取指定路径的配置并解析到指定的值中
+func (c *Client) GetConfigInto(path string, v interface{}) error {
 	url := c.GetConfigURL(path)
 	resp, err := c.HTTPClient.Get(url)
 	if err != nil {
-		return nil, fmt.Errorf("获取配置失败: %w", err)
+		return fmt.Errorf("获取配置失败: %w", err)
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("获取配置失败, 状态码: %d", resp.StatusCode)
+		return fmt.Errorf("获取配置失败, 状态码: %d", resp.StatusCode)
 	}
 
-	var result map[string]interface{}
-	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
-		return nil, fmt.Errorf("解析响应 JSON 失败: %w", err)
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		return fmt.Errorf("解析响应 JSON 失败: %w", err)
 	}
 
-	return result, nil
+	return nil
+}
+
+// GetConfigWithEtag 获取指定路径的配置并解析到指定的值中，同时返回响应的 Etag
+// Etag 可用于 LoadIfMatch，在配置未被其他客户端修改时才替换
+func (c *Client) GetConfigWithEtag(path string, v interface{}) (string, error) {
+	url := c.GetConfigURL(path)
+	resp, err := c.HTTPClient.Get(url)
+	if err != nil {
+		return "", fmt.Errorf("获取配置失败: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("获取配置失败, 状态码: %d", resp.StatusCode)
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		return "", fmt.Errorf("解析响应 JSON 失败: %w", err)
+	}
+
+	return resp.Header.Get("Etag"), nil
 }
 
 // HasID 检查指定 ID 是否已设置 - 对应 Python 的 has_id(id) 函数
@@ -106,18 +147,130 @@ func (c *Client) HasPath(path string) bool {
 	return err == nil
 }
 
+// HasValue 检查指定路径是否有值 (任意类型)
+// Caddy 对不存在的最后一级键返回 null，因此值为 null 时视为没有值
+func (c *Client) HasValue(path string) bool {
+	var value interface{}
+	err := c.GetConfigInto(path, &value)
+	return err == nil && value != nil
+}
+
+// validate 写入前在本地校验要写入的配置，避免 Caddy 拒绝前已经写入部分配置
+// 只校验写入的部分，正在运行的配置中的其他内容不影响写入；警告 (如未知的处理器) 不阻止写入
+func (c *Client) validate(data interface{}, path string) error {
+	if c.SkipValidation || data == nil {
+		return nil
+	}
+	if err := types.Validate(data); err != nil {
+		return fmt.Errorf("配置校验失败 (%s): %w", path, err)
+	}
+	return nil
+}
+
+// checkIDs 检查写入的 @id 是否已被正在运行的配置中的其他对象使用
+// 一次获取完整配置后在本地比较；PATCH (以及 POST 到非数组的值) 会替换 url 处的原有配置，其中的 @id 不算重复
+func (c *Client) checkIDs(data interface{}, method, url, path string) error {
+	if c.SkipValidation || data == nil {
+		return nil
+	}
+	ids, err := types.CollectIDs(data)
+	if err != nil || len(ids) == 0 {
+		return err
+	}
+
+	var config interface{}
+	if err := c.GetConfigInto("/", &config); err != nil {
+		return err
+	}
+	live, err := types.CollectIDs(config)
+	if err != nil {
+		return err
+	}
+	count := make(map[string]int)
+	for _, id := range live {
+		count[id]++
+	}
+
+	if method == "PATCH" || method == "POST" {
+		var current interface{}
+		if c.getJSON(url, &current) == nil {
+			if _, isArray := current.([]interface{}); method == "PATCH" || !isArray {
+				existing, _ := types.CollectIDs(current)
+				for _, id := range existing {
+					count[id]--
+				}
+			}
+		}
+	}
+
+	for _, id := range ids {
+		if count[id] > 0 {
+			return fmt.Errorf("配置校验失败 (%s): @id 已存在: %s", path, id)
+		}
+	}
+	return nil
+}
+
+// getJSON 获取指定 URL 的配置并解析到指定的值中
+func (c *Client) getJSON(url string, v interface{}) error {
+	resp, err := c.HTTPClient.Get(url)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("状态码: %d", resp.StatusCode)
+	}
+	return json.NewDecoder(resp.Body).Decode(v)
+}
+
 // PutByID 将配置数据放入指定 ID 路径 - 对应 Python 的 pid(d, path, method) 函数
 func (c *Client) PutByID(data interface{}, path, method string) error {
+	if err := c.validate(data, path); err != nil {
+		return err
+	}
 	url := c.GetIDURL(path)
+	if err := c.checkIDs(data, method, url, path); err != nil {
+		return err
+	}
 	return c.sendRequest(method, url, data)
 }
 
 // PutConfig 将配置数据放入指定配置路径 - 对应 Python 的 pcfg(d, path, method) 函数
 func (c *Client) PutConfig(data interface{}, path, method string) error {
+	if err := c.validate(data, path); err != nil {
+		return err
+	}
 	url := c.GetConfigURL(path)
+	if err := c.checkIDs(data, method, url, path); err != nil {
+		return err
+	}
 	return c.sendRequest(method, url, data)
 }
 
+// DeleteConfig 删除指定配置路径
+func (c *Client) DeleteConfig(path string) error {
+	url := c.GetConfigURL(path)
+	return c.sendRequest("DELETE", url, nil)
+}
+
+// Patch 替换指定路径已存在的值 (PATCH)，路径不存在时 Caddy 返回错误
+func (c *Client) Patch(path string, data interface{}) error {
+	return c.PutConfig(data, path, "PATCH")
+}
+
+// Insert 在数组的指定下标处插入元素 (PUT 到 path/index)，原有元素依次后移
+// index 等于数组长度时追加到末尾
+func (c *Client) Insert(path string, index int, data interface{}) error {
+	return c.PutConfig(data, fmt.Sprintf("%s/%d", strings.TrimSuffix(path, "/"), index), "PUT")
+}
+
+// Append 将元素追加到数组末尾 (POST 到数组路径)
+// 注意 Caddy 对非数组的值执行 POST 会直接替换，path 必须指向已存在的数组
+func (c *Client) Append(path string, data interface{}) error {
+	return c.PutConfig(data, path, "POST")
+}
+
 // DeleteByID 删除指定 ID 的配置 - 对应 Python 的 del_id(id) 函数
 func (c *Client) DeleteByID(id string) error {
 	url := c.GetIDURL(id)
@@ -167,16 +320,21 @@ func (c *Client) sendRequest(method, url string, data interface{}) error {
 
 	// 检查响应状态码
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		// 尝试读取错误信息
-		body, _ := io.ReadAll(resp.Body)
-		var errorMsg map[string]interface{}
-		if json.Unmarshal(body, &errorMsg) == nil {
-			if errStr, ok := errorMsg["error"].(string); ok {
-				return fmt.Errorf("请求失败, 状态码: %d, 错误: %s", resp.StatusCode, errStr)
-			}
-		}
-		return fmt.Errorf("请求失败, 状态码: %d", resp.StatusCode)
+		return responseError(resp)
 	}
 
 	return nil
+}
+
+// responseError 根据失败的响应生成错误，尽量包含 Caddy 返回的错误信息
+func responseError(resp *http.Response) error {
+	// 尝试读取错误信息
+	body, _ := io.ReadAll(resp.Body)
+	var errorMsg map[string]interface{}
+	if json.Unmarshal(body, &errorMsg) == nil {
+		if errStr, ok := errorMsg["error"].(string); ok {
+			return fmt.Errorf("请求失败, 状态码: %d, 错误: %s", resp.StatusCode, errStr)
+		}
+	}
+	return fmt.Errorf("请求失败, 状态码: %d", resp.StatusCode)
 }
\ No newline at end of file
@@ -0,0 +1,216 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/youfun/fastcaddy/pkg/types"
+)
+
+// configPath 将 JSON Pointer 的各级键转换为 Caddy 配置路径
+// Caddy 配置路径以 '/' 分隔且不支持转义，因此键中不能包含 '/'
+func configPath(path []string) (string, error) {
+	for _, token := range path {
+		if strings.Contains(token, "/") {
+			return "", fmt.Errorf("键 %q 包含 '/'，无法通过配置路径访问", token)
+		}
+	}
+	return "/" + strings.Join(path, "/"), nil
+}
+
+// PreviewPatch 在正在运行的配置的副本上应用 JSON Patch，返回修改后的完整配置
+// 正在运行的配置不会被修改；补丁写入的值会在本地校验
+func (c *Client) PreviewPatch(patch types.JSONPatch) (json.RawMessage, error) {
+	var config interface{}
+	if err := c.GetConfigInto("/", &config); err != nil {
+		return nil, fmt.Errorf("获取配置失败: %w", err)
+	}
+	result, err := patch.Apply(config)
+	if err != nil {
+		return nil, err
+	}
+	if err := c.validatePatch(patch, result); err != nil {
+		return nil, err
+	}
+	return json.Marshal(result)
+}
+
+// ApplyPatch 在本地完整预览 JSON Patch (包括 test 操作和配置校验)，再通过 /load 一次性提交结果
+// 任一操作失败时不修改配置；提交时带上读取配置时的 Etag，期间配置如被其他客户端修改则返回 ErrConfigChanged
+func (c *Client) ApplyPatch(patch types.JSONPatch) error {
+	var doc interface{}
+	etag, err := c.GetConfigWithEtag("/", &doc)
+	if err != nil {
+		return fmt.Errorf("获取配置失败: %w", err)
+	}
+	result, err := patch.Apply(doc)
+	if err != nil {
+		return err
+	}
+	if err := c.validatePatch(patch, result); err != nil {
+		return err
+	}
+
+	// 补丁写入的部分已校验，提交时不再整体校验 (正在运行的配置中的插件处理器等不影响提交)
+	client := *c
+	client.SkipValidation = true
+	return client.LoadIfMatch(result, etag)
+}
+
+// ApplyPatchOps 将 JSON Patch 转换为 /config/ 请求逐个执行，不整体替换配置
+// 执行前同样先在本地完整预览，任一操作失败时不发送任何请求。
+// add 对已存在的对象成员使用 PATCH、新成员使用 PUT，数组使用 Insert 或 Append；remove 使用 DELETE；
+// replace 使用 PATCH；move 和 copy 拆分为删除和添加；test 只在本地检查。
+// 请求之间不是原子的，Caddy 拒绝其中某个请求时之前的操作不会撤销，一般应使用 ApplyPatch
+func (c *Client) ApplyPatchOps(patch types.JSONPatch) error {
+	var doc interface{}
+	if err := c.GetConfigInto("/", &doc); err != nil {
+		return fmt.Errorf("获取配置失败: %w", err)
+	}
+	result, err := patch.Apply(doc)
+	if err != nil {
+		return err
+	}
+	if err := c.validatePatch(patch, result); err != nil {
+		return err
+	}
+
+	for i, op := range patch {
+		steps, err := patchSteps(doc, op)
+		if err != nil {
+			return fmt.Errorf("第 %d 个操作 (%s) 失败: %w", i+1, op, err)
+		}
+		for _, step := range steps {
+			if err := c.patchRequest(doc, step); err != nil {
+				return fmt.Errorf("第 %d 个操作 (%s) 失败: %w", i+1, op, err)
+			}
+			if doc, err = step.Apply(doc); err != nil {
+				return fmt.Errorf("第 %d 个操作 (%s) 失败: %w", i+1, op, err)
+			}
+		}
+	}
+	return nil
+}
+
+// validatePatch 校验补丁写入的值 (add、replace)，并检查其中的 @id 在结果中是否唯一
+// 只校验补丁写入的部分，正在运行的配置中的其他内容不影响写入
+func (c *Client) validatePatch(patch types.JSONPatch, result interface{}) error {
+	if c.SkipValidation {
+		return nil
+	}
+	var ids []string
+	for i, op := range patch {
+		if op.Op != types.PatchAdd && op.Op != types.PatchReplace {
+			continue
+		}
+		if err := c.validate(op.Value, op.Path); err != nil {
+			return fmt.Errorf("第 %d 个操作 (%s) 失败: %w", i+1, op, err)
+		}
+		opIDs, err := types.CollectIDs(op.Value)
+		if err != nil {
+			return err
+		}
+		ids = append(ids, opIDs...)
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+
+	all, err := types.CollectIDs(result)
+	if err != nil {
+		return err
+	}
+	count := make(map[string]int)
+	for _, id := range all {
+		count[id]++
+	}
+	for _, id := range ids {
+		if count[id] > 1 {
+			return fmt.Errorf("配置校验失败: @id 重复: %s", id)
+		}
+	}
+	return nil
+}
+
+// patchSteps 将 move 和 copy 拆分为 Caddy 支持的删除和添加操作
+func patchSteps(doc interface{}, op types.PatchOperation) ([]types.PatchOperation, error) {
+	if op.Op != types.PatchMove && op.Op != types.PatchCopy {
+		return []types.PatchOperation{op}, nil
+	}
+	if op.Op == types.PatchMove && op.From == op.Path {
+		return nil, nil
+	}
+
+	from, err := types.ParsePointer(op.From)
+	if err != nil {
+		return nil, err
+	}
+	value, err := types.GetPointer(doc, from)
+	if err != nil {
+		return nil, err
+	}
+
+	add := types.PatchOperation{Op: types.PatchAdd, Path: op.Path, Value: value}
+	if op.Op == types.PatchCopy {
+		return []types.PatchOperation{add}, nil
+	}
+	return []types.PatchOperation{{Op: types.PatchRemove, Path: op.From}, add}, nil
+}
+
+// patchRequest 按照 doc (执行该操作前的配置) 将单个 add、remove、replace 或 test 操作发送给 Caddy
+func (c *Client) patchRequest(doc interface{}, op types.PatchOperation) error {
+	path, err := types.ParsePointer(op.Path)
+	if err != nil {
+		return err
+	}
+	if op.Op == types.PatchTest {
+		return nil
+	}
+	if len(path) == 0 {
+		// 添加或替换整个配置
+		return c.Load(op.Value)
+	}
+
+	target, err := configPath(path)
+	if err != nil {
+		return err
+	}
+	switch op.Op {
+	case types.PatchRemove:
+		return c.DeleteConfig(target)
+	case types.PatchReplace:
+		return c.Patch(target, op.Value)
+	case types.PatchAdd:
+		return c.patchAdd(doc, path, target, op.Value)
+	}
+	return fmt.Errorf("未知的操作类型: %q", op.Op)
+}
+
+// patchAdd 发送 add 操作 - 根据父容器的类型和成员是否存在选择请求方法
+func (c *Client) patchAdd(doc interface{}, path []string, target string, value interface{}) error {
+	parentPath, _ := configPath(path[:len(path)-1])
+	parent, err := types.GetPointer(doc, path[:len(path)-1])
+	if err != nil {
+		return err
+	}
+	key := path[len(path)-1]
+	switch val := parent.(type) {
+	case []interface{}:
+		if key == "-" {
+			return c.Append(parentPath, value)
+		}
+		index, err := strconv.Atoi(key)
+		if err != nil {
+			return fmt.Errorf("无效的数组下标: %q", key)
+		}
+		return c.Insert(parentPath, index, value)
+	case map[string]interface{}:
+		if _, exists := val[key]; exists {
+			return c.Patch(target, value)
+		}
+		return c.PutConfig(value, target, "PUT")
+	}
+	return fmt.Errorf("父路径不是对象或数组: %s", parentPath)
+}
@@ -0,0 +1,371 @@
+package api
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Stage 暂存配置 - 在内存中模拟 Caddy 管理接口的 /config、/id 和 /load (包括 Etag 和 If-Match)
+// 作为 HTTP 客户端的 Transport 使用时，所有配置读写都作用于内存中的配置副本，
+// 其他请求 (如 /adapt) 转发给 next
+type Stage struct {
+	mu     sync.Mutex
+	config interface{}
+	next   http.RoundTripper
+}
+
+// NewStage 基于配置的副本创建暂存配置
+func NewStage(config interface{}, next http.RoundTripper) (*Stage, error) {
+	copied, err := deepCopy(config)
+	if err != nil {
+		return nil, err
+	}
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	return &Stage{config: copied, next: next}, nil
+}
+
+// WithStage 返回使用暂存配置的客户端副本
+func (c *Client) WithStage(stage *Stage) *Client {
+	httpClient := *c.HTTPClient
+	httpClient.Transport = stage
+	return &Client{
+		BaseURL:        c.BaseURL,
+		HTTPClient:     &httpClient,
+		SkipValidation: c.SkipValidation,
+	}
+}
+
+// Config 返回暂存配置的 JSON
+func (s *Stage) Config() (json.RawMessage, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return json.Marshal(s.config)
+}
+
+// deepCopy 通过 JSON 序列化复制配置
+func deepCopy(v interface{}) (interface{}, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, fmt.Errorf("序列化配置失败: %w", err)
+	}
+	var copied interface{}
+	if err := json.Unmarshal(data, &copied); err != nil {
+		return nil, fmt.Errorf("解析配置失败: %w", err)
+	}
+	return copied, nil
+}
+
+// stageError 暂存配置操作错误，带有响应状态码
+type stageError struct {
+	status  int
+	message string
+}
+
+// Error 返回错误信息
+func (e *stageError) Error() string {
+	return e.message
+}
+
+// newStageError 创建暂存配置操作错误
+func newStageError(status int, format string, args ...interface{}) *stageError {
+	return &stageError{status: status, message: fmt.Sprintf(format, args...)}
+}
+
+// RoundTrip 处理请求 - /config、/id 和 /load 在内存中执行，其余请求转发
+func (s *Stage) RoundTrip(req *http.Request) (*http.Response, error) {
+	path := req.URL.Path
+	var parts []string
+	switch {
+	case path == "/load":
+		parts = nil
+	case strings.HasPrefix(path, "/config/") || path == "/config":
+		parts = splitParts(strings.TrimPrefix(path, "/config"))
+	case strings.HasPrefix(path, "/id/"):
+		parts = splitParts(strings.TrimPrefix(path, "/id/"))
+	default:
+		return s.next.RoundTrip(req)
+	}
+
+	var val interface{}
+	if req.Body != nil {
+		body, err := io.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		if len(bytes.TrimSpace(body)) > 0 {
+			if err := json.Unmarshal(body, &val); err != nil {
+				return respond(req, http.StatusBadRequest, map[string]string{"error": fmt.Sprintf("解析请求 JSON 失败: %v", err)}), nil
+			}
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if ifMatch := req.Header.Get("If-Match"); ifMatch != "" && req.Method != http.MethodGet {
+		if err := s.checkEtag(ifMatch); err != nil {
+			return respond(req, err.status, map[string]string{"error": err.message}), nil
+		}
+	}
+
+	if path == "/load" {
+		if req.Method != http.MethodPost {
+			return respond(req, http.StatusMethodNotAllowed, map[string]string{"error": "method not allowed"}), nil
+		}
+		s.config = val
+		return respond(req, http.StatusOK, nil), nil
+	}
+
+	if strings.HasPrefix(path, "/id/") {
+		if len(parts) == 0 {
+			return respond(req, http.StatusBadRequest, map[string]string{"error": "missing ID"}), nil
+		}
+		idPath, ok := findID(s.config, parts[0], nil)
+		if !ok {
+			return respond(req, http.StatusNotFound, map[string]string{"error": "unknown object ID '" + parts[0] + "'"}), nil
+		}
+		parts = append(idPath, parts[1:]...)
+	}
+
+	if req.Method == http.MethodGet {
+		value, err := get(s.config, parts)
+		if err != nil {
+			return respond(req, err.status, map[string]string{"error": err.message}), nil
+		}
+		resp := respond(req, http.StatusOK, value)
+		if strings.HasPrefix(path, "/config") {
+			resp.Header.Set("Etag", fmt.Sprintf("%q", path+" "+etagHash(value)))
+		}
+		return resp, nil
+	}
+
+	config, err := apply(s.config, parts, req.Method, val)
+	if err != nil {
+		return respond(req, err.status, map[string]string{"error": err.message}), nil
+	}
+	s.config = config
+	return respond(req, http.StatusOK, nil), nil
+}
+
+// checkEtag 检查 If-Match 中的 Etag ("<路径> <哈希>") 是否与该路径当前的配置一致
+func (s *Stage) checkEtag(ifMatch string) *stageError {
+	fields := strings.Fields(strings.Trim(ifMatch, `"`))
+	if len(fields) != 2 || !strings.HasPrefix(fields[0], "/config") {
+		return newStageError(http.StatusBadRequest, "malformed If-Match header")
+	}
+	value, err := get(s.config, splitParts(strings.TrimPrefix(fields[0], "/config")))
+	if err != nil {
+		return err
+	}
+	if etagHash(value) != fields[1] {
+		return newStageError(http.StatusPreconditionFailed, "If-Match header did not match current config hash")
+	}
+	return nil
+}
+
+// etagHash 计算配置值的哈希，用于 Etag
+func etagHash(value interface{}) string {
+	data, _ := json.Marshal(value)
+	h := fnv.New64a()
+	h.Write(data)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// splitParts 将配置路径拆分为各级键，忽略空组件
+func splitParts(path string) []string {
+	var parts []string
+	for _, part := range strings.Split(path, "/") {
+		if part != "" {
+			parts = append(parts, part)
+		}
+	}
+	return parts
+}
+
+// respond 构建 JSON 响应
+func respond(req *http.Request, status int, body interface{}) *http.Response {
+	data := []byte("null")
+	if body != nil {
+		data, _ = json.Marshal(body)
+	}
+	return &http.Response{
+		StatusCode:    status,
+		Status:        fmt.Sprintf("%d %s", status, http.StatusText(status)),
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        http.Header{"Content-Type": {"application/json"}},
+		Body:          io.NopCloser(bytes.NewReader(data)),
+		ContentLength: int64(len(data)),
+		Request:       req,
+	}
+}
+
+// findID 查找 @id 对应的配置路径
+func findID(node interface{}, id string, path []string) ([]string, bool) {
+	switch val := node.(type) {
+	case map[string]interface{}:
+		if val["@id"] == id {
+			return path, true
+		}
+		for key, child := range val {
+			if found, ok := findID(child, id, append(append([]string{}, path...), key)); ok {
+				return found, true
+			}
+		}
+	case []interface{}:
+		for i, child := range val {
+			if found, ok := findID(child, id, append(append([]string{}, path...), strconv.Itoa(i))); ok {
+				return found, true
+			}
+		}
+	}
+	return nil, false
+}
+
+// get 获取指定路径的值
+// 与 Caddy 相同，最后一级键不存在时返回 null，中间路径不存在时返回错误
+func get(node interface{}, parts []string) (interface{}, *stageError) {
+	for i, part := range parts {
+		switch val := node.(type) {
+		case map[string]interface{}:
+			child, ok := val[part]
+			if !ok && i < len(parts)-1 {
+				return nil, newStageError(http.StatusBadRequest, "invalid traversal path at: %s", strings.Join(parts[:i+1], "/"))
+			}
+			node = child
+		case []interface{}:
+			index, err := strconv.Atoi(part)
+			if err != nil || index < 0 || index >= len(val) {
+				return nil, newStageError(http.StatusBadRequest, "invalid traversal path at: %s", strings.Join(parts[:i+1], "/"))
+			}
+			node = val[index]
+		default:
+			return nil, newStageError(http.StatusBadRequest, "invalid traversal path at: %s", strings.Join(parts[:i+1], "/"))
+		}
+	}
+	return node, nil
+}
+
+// apply 按 Caddy 管理接口的语义修改配置，返回修改后的节点
+// POST 设置对象的键或追加到数组；PUT 创建新的键或在数组指定位置插入；PATCH 替换已有的值；DELETE 删除。
+// 与 Caddy 相同，任何方法都不会自动创建路径中缺失的对象
+func apply(node interface{}, parts []string, method string, val interface{}) (interface{}, *stageError) {
+	return applyAt(node, parts, 0, method, val)
+}
+
+// applyAt 从第 i 级键开始修改配置，修改后依次写回各级父容器
+func applyAt(node interface{}, parts []string, i int, method string, val interface{}) (interface{}, *stageError) {
+	if i == len(parts) {
+		switch method {
+		case http.MethodPost, http.MethodPatch:
+			if arr, ok := node.([]interface{}); ok && method == http.MethodPost {
+				return append(arr, val), nil
+			}
+			return val, nil
+		case http.MethodPut:
+			if node != nil {
+				return nil, newStageError(http.StatusConflict, "config already exists")
+			}
+			return val, nil
+		case http.MethodDelete:
+			return nil, nil
+		}
+		return nil, newStageError(http.StatusMethodNotAllowed, "method not allowed")
+	}
+
+	part, last := parts[i], i == len(parts)-1
+	switch v := node.(type) {
+	case map[string]interface{}:
+		child, exists := v[part]
+		if last {
+			switch method {
+			case http.MethodPost:
+				if arr, ok := child.([]interface{}); ok {
+					v[part] = append(arr, val)
+				} else {
+					v[part] = val
+				}
+			case http.MethodPut:
+				if exists {
+					return nil, newStageError(http.StatusConflict, "key already exists: %s", part)
+				}
+				v[part] = val
+			case http.MethodPatch:
+				if !exists {
+					return nil, newStageError(http.StatusNotFound, "key does not exist: %s", part)
+				}
+				v[part] = val
+			case http.MethodDelete:
+				if !exists {
+					return nil, newStageError(http.StatusNotFound, "key does not exist: %s", part)
+				}
+				delete(v, part)
+			default:
+				return nil, newStageError(http.StatusMethodNotAllowed, "method not allowed")
+			}
+			return v, nil
+		}
+
+		if !exists {
+			return nil, newStageError(http.StatusBadRequest, "invalid traversal path at: %s", strings.Join(parts[:i+2], "/"))
+		}
+		updated, err := applyAt(child, parts, i+1, method, val)
+		if err != nil {
+			return nil, err
+		}
+		v[part] = updated
+		return v, nil
+
+	case []interface{}:
+		index, err := strconv.Atoi(part)
+		if err != nil || index < 0 {
+			return nil, newStageError(http.StatusBadRequest, "invalid array index: %s", part)
+		}
+		if last {
+			switch method {
+			case http.MethodPut:
+				if index > len(v) {
+					return nil, newStageError(http.StatusBadRequest, "array index out of bounds: %s", part)
+				}
+				v = append(v[:index], append([]interface{}{val}, v[index:]...)...)
+			case http.MethodPost, http.MethodPatch:
+				if index >= len(v) {
+					return nil, newStageError(http.StatusBadRequest, "array index out of bounds: %s", part)
+				}
+				v[index] = val
+			case http.MethodDelete:
+				if index >= len(v) {
+					return nil, newStageError(http.StatusBadRequest, "array index out of bounds: %s", part)
+				}
+				v = append(v[:index], v[index+1:]...)
+			default:
+				return nil, newStageError(http.StatusMethodNotAllowed, "method not allowed")
+			}
+			return v, nil
+		}
+
+		if index >= len(v) {
+			return nil, newStageError(http.StatusBadRequest, "array index out of bounds: %s", part)
+		}
+		updated, serr := applyAt(v[index], parts, i+1, method, val)
+		if serr != nil {
+			return nil, serr
+		}
+		v[index] = updated
+		return v, nil
+	}
+
+	return nil, newStageError(http.StatusBadRequest, "invalid traversal path at: %s", strings.Join(parts[:i+1], "/"))
+}
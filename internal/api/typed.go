@@ -0,0 +1,32 @@
+package api
+
+// Get 获取指定路径的配置并解析为类型 T
+// 例如 api.Get[types.HTTPApp](client, "/apps/http")、api.Get[types.CaddyConfig](client, "/")
+func Get[T any](c *Client, path string) (T, error) {
+	var value T
+	if err := c.GetConfigInto(path, &value); err != nil {
+		return value, err
+	}
+	return value, nil
+}
+
+// GetID 通过 ID 获取配置并解析为类型 T
+// 例如 api.GetID[types.Route](client, "example.com")
+func GetID[T any](c *Client, id string) (T, error) {
+	var value T
+	if err := c.GetByIDInto(id, &value); err != nil {
+		return value, err
+	}
+	return value, nil
+}
+
+// Put 将类型化的配置写入指定路径，method 与 PutConfig 相同 (POST、PUT、PATCH)
+// 类型化结构会原样写回读取时保留的未建模字段
+func Put[T any](c *Client, path string, value T, method string) error {
+	return c.PutConfig(value, path, method)
+}
+
+// PutID 将类型化的配置写入指定 ID 路径
+func PutID[T any](c *Client, id string, value T, method string) error {
+	return c.PutByID(value, id, method)
+}
@@ -17,6 +17,13 @@ func NewManager() *Manager {
 	}
 }
 
+// NewManagerWithClient 使用指定的 API 客户端创建配置管理器
+func NewManagerWithClient(client *api.Client) *Manager {
+	return &Manager{
+		client: client,
+	}
+}
+
 // NestedSetDict 在嵌套字典中设置值 - 对应 Python 的 nested_setdict(sd, value, *keys) 函数
 // 返回更新后的字典，其中在指定键路径处设置了值
 func NestedSetDict(dict map[string]interface{}, value interface{}, keys ...string) map[string]interface{} {
@@ -113,6 +120,23 @@ func (m *Manager) InitPath(path string, skip int) error {
 	return nil
 }
 
+// EnsurePath 确保配置路径存在 - 与 InitPath 不同，只创建缺失的层级，不会覆盖已有配置
+func (m *Manager) EnsurePath(path string) error {
+	keys := PathToKeys(path)
+
+	// 从最深的层级向上查找第一个已存在的路径
+	existing := 0
+	for i := len(keys); i > 0; i-- {
+		if m.client.HasValue(KeysToPath(keys[:i]...)) {
+			existing = i
+			break
+		}
+	}
+
+	// 只初始化缺失的层级
+	return m.InitPath(path, existing)
+}
+
 // GetClient 获取底层 API 客户端 - 提供对原始 API 的访问
 func (m *Manager) GetClient() *api.Client {
 	return m.client
@@ -0,0 +1,85 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/youfun/fastcaddy/pkg/types"
+)
+
+// ValidateConfig 在本地校验完整的 Caddy JSON 配置，只返回错误 (忽略警告)
+// 除 types.Validate 的结构检查外，还检查 JSON 语法和每个 HTTP 服务器的监听地址
+func ValidateConfig(data []byte) error {
+	issues, err := CheckConfig(data)
+	if err != nil {
+		return err
+	}
+	if errs := issues.Errors(); len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+// CheckConfig 与 ValidateConfig 相同，但返回所有错误和警告
+func CheckConfig(data []byte) (types.ValidationErrors, error) {
+	var cfg map[string]interface{}
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		var syntaxErr *json.SyntaxError
+		if errors.As(err, &syntaxErr) {
+			line, col := position(data, syntaxErr.Offset)
+			return nil, fmt.Errorf("JSON 语法错误 (第 %d 行第 %d 列): %w", line, col, err)
+		}
+		return nil, fmt.Errorf("解析配置失败: %w", err)
+	}
+
+	errs, err := types.Check(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	apps, _ := cfg["apps"].(map[string]interface{})
+	httpApp, _ := apps["http"].(map[string]interface{})
+	servers, _ := httpApp["servers"].(map[string]interface{})
+	names := make([]string, 0, len(servers))
+	for name := range servers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		server, _ := servers[name].(map[string]interface{})
+		if _, ok := server["listen"]; !ok {
+			errs = append(errs, types.ValidationError{
+				Path:    fmt.Sprintf("apps/http/servers/%s/listen", name),
+				Message: "至少需要一个监听地址",
+			})
+		}
+	}
+
+	return errs, nil
+}
+
+// ValidateFile 校验 JSON 配置文件
+func ValidateFile(filename string) error {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return fmt.Errorf("读取配置文件失败: %w", err)
+	}
+	return ValidateConfig(data)
+}
+
+// position 将字节偏移转换为行号和列号 (从 1 开始)
+func position(data []byte, offset int64) (int, int) {
+	line, col := 1, 1
+	for i := int64(0); i < offset && i < int64(len(data)); i++ {
+		if data[i] == '\n' {
+			line++
+			col = 1
+		} else {
+			col++
+		}
+	}
+	return line, col
+}
@@ -0,0 +1,1070 @@
+package export
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/youfun/fastcaddy/pkg/types"
+)
+
+// indent Caddyfile 缩进
+const indent = "\t"
+
+// site 站点块 - 具有相同主机名的路由
+type site struct {
+	addresses []string
+	routes    []types.Route
+	errors    []types.Route
+}
+
+// renderer Caddyfile 渲染器
+type renderer struct {
+	b        strings.Builder
+	depth    int
+	logs     map[string]*types.CustomLog
+	matchers map[string]bool // 当前站点块中已使用的命名匹配器
+	global   []string        // 需要添加到全局选项的额外行
+}
+
+// Caddyfile 将 Caddy JSON 配置渲染为近似的 Caddyfile
+// 覆盖 fastcaddy 生成的处理器 (reverse_proxy、subroute、file_server、static_response、headers 等)，
+// 无法表达的部分以注释形式输出；结果用于审阅和文档，不保证能够原样导入
+func Caddyfile(data []byte) (string, error) {
+	var cfg types.CaddyConfig
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return "", fmt.Errorf("解析配置失败: %w", err)
+	}
+	if cfg.Apps == nil {
+		cfg.Apps = &types.Apps{}
+	}
+	httpCfg := cfg.Apps.HTTP
+	if httpCfg == nil {
+		httpCfg = &types.HTTPApp{}
+	}
+
+	r := &renderer{}
+	if cfg.Logging != nil {
+		r.logs = cfg.Logging.Logs
+	}
+
+	// 先渲染站点块，收集需要的全局选项
+	sites := &renderer{logs: r.logs}
+	names := make([]string, 0, len(httpCfg.Servers))
+	for name := range httpCfg.Servers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		sites.server(name, httpCfg.Servers[name])
+	}
+
+	r.globalOptions(cfg, httpCfg, sites.global)
+	r.b.WriteString(sites.b.String())
+	return strings.TrimRight(r.b.String(), "\n") + "\n", nil
+}
+
+// line 输出一行
+func (r *renderer) line(format string, args ...interface{}) {
+	r.b.WriteString(strings.Repeat(indent, r.depth))
+	fmt.Fprintf(&r.b, format, args...)
+	r.b.WriteString("\n")
+}
+
+// comment 输出注释行
+func (r *renderer) comment(format string, args ...interface{}) {
+	r.line("# "+format, args...)
+}
+
+// open 输出块的开始行
+func (r *renderer) open(format string, args ...interface{}) {
+	r.line(format+" {", args...)
+	r.depth++
+}
+
+// close 输出块的结束行
+func (r *renderer) close() {
+	r.depth--
+	r.line("}")
+}
+
+// blank 输出空行
+func (r *renderer) blank() {
+	r.b.WriteString("\n")
+}
+
+// bareToken 无需引号的 Caddyfile 标记
+var bareToken = regexp.MustCompile(`^[^\s"` + "`" + `]+$`)
+
+// quote 按需为 Caddyfile 标记添加引号
+// 包含空白或引号、以 # 开头以及单独的花括号需要加引号
+func quote(s string) string {
+	if bareToken.MatchString(s) && !strings.HasPrefix(s, "#") && s != "{" && s != "}" {
+		return s
+	}
+	return `"` + strings.ReplaceAll(s, `"`, `\"`) + `"`
+}
+
+// quoteAll 为多个标记添加引号并以空格连接
+func quoteAll(values []string) string {
+	quoted := make([]string, len(values))
+	for i, v := range values {
+		quoted[i] = quote(v)
+	}
+	return strings.Join(quoted, " ")
+}
+
+// sortedKeys 返回排序后的键
+func sortedKeys(m map[string][]string) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// globalOptions 输出全局选项块 (TLS 证书颁发者、PKI 和服务器选项)，没有全局选项时不输出
+func (r *renderer) globalOptions(cfg types.CaddyConfig, httpCfg *types.HTTPApp, extra []string) {
+	g := &renderer{depth: r.depth + 1}
+	for _, line := range extra {
+		g.line("%s", line)
+	}
+
+	if cfg.Apps.TLS != nil {
+		g.tlsOptions(cfg.Apps.TLS)
+	}
+	if cfg.Apps.PKI != nil {
+		if ca := cfg.Apps.PKI.CertificateAuthorities["local"]; ca != nil && ca.InstallTrust != nil && !*ca.InstallTrust {
+			g.line("skip_install_trust")
+		}
+	}
+	for _, name := range sortedAppNames(cfg.Apps.Extra) {
+		g.comment("应用 %s 无法转换为 Caddyfile", name)
+	}
+
+	names := make([]string, 0, len(httpCfg.Servers))
+	for name := range httpCfg.Servers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		g.serverOptions(name, httpCfg.Servers[name])
+	}
+
+	if g.b.Len() == 0 {
+		return
+	}
+	r.line("{")
+	r.b.WriteString(g.b.String())
+	r.line("}")
+	r.blank()
+}
+
+// sortedAppNames 返回渲染器不支持的应用名称
+func sortedAppNames(apps types.Extra) []string {
+	names := make([]string, 0, len(apps))
+	for name := range apps {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// tlsOptions 输出 TLS 自动化策略对应的全局选项
+func (r *renderer) tlsOptions(app *types.TLSApp) {
+	if app.Automation == nil {
+		r.comment("TLS 配置无法转换")
+		return
+	}
+
+	for _, policy := range app.Automation.Policies {
+		if len(policy.Subjects) > 0 {
+			r.comment("以下证书颁发者仅用于: %s", strings.Join(policy.Subjects, ", "))
+		}
+		for _, issuer := range policy.Issuers {
+			switch issuer.Module {
+			case "internal":
+				r.line("local_certs")
+			case "acme":
+				if issuer.Email != "" {
+					r.line("email %s", quote(issuer.Email))
+				}
+				if issuer.CA != "" {
+					r.line("acme_ca %s", quote(issuer.CA))
+				}
+				if c := issuer.Challenges; c != nil && c.DNS != nil && c.DNS.Provider != nil && c.DNS.Provider.Name != "" {
+					// 令牌不导出，通过环境变量提供
+					name := c.DNS.Provider.Name
+					r.comment("API 令牌未导出，请通过环境变量 %s_API_TOKEN 提供", strings.ToUpper(name))
+					r.line("acme_dns %s {env.%s_API_TOKEN}", name, strings.ToUpper(name))
+				}
+			default:
+				r.comment("证书颁发者 %s 无法转换", issuer.Module)
+			}
+		}
+	}
+}
+
+// serverOptions 输出服务器级别的全局选项 (协议、受信任代理、超时等)
+func (r *renderer) serverOptions(name string, server *types.HTTPServer) {
+	hasOptions := len(server.Protocols) > 0 || len(server.ListenerWrappers) > 0 || server.TrustedProxies != nil ||
+		len(server.ClientIPHeaders) > 0 || server.ReadTimeout != "" || server.ReadHeaderTimeout != "" ||
+		server.WriteTimeout != "" || server.IdleTimeout != ""
+	if !hasOptions {
+		return
+	}
+
+	// servers 选项只能指定一个监听地址，多个地址时应用到所有服务器
+	r.comment("服务器 %s", name)
+	if len(server.Listen) == 1 {
+		r.open("servers %s", server.Listen[0])
+	} else {
+		r.open("servers")
+	}
+	if len(server.Protocols) > 0 {
+		r.line("protocols %s", strings.Join(server.Protocols, " "))
+	}
+	if len(server.ListenerWrappers) > 0 {
+		r.open("listener_wrappers")
+		for _, wrapper := range server.ListenerWrappers {
+			if wrapper.Timeout == "" && len(wrapper.Allow) == 0 {
+				r.line("%s", wrapper.Wrapper)
+				continue
+			}
+			r.open("%s", wrapper.Wrapper)
+			if wrapper.Timeout != "" {
+				r.line("timeout %s", wrapper.Timeout)
+			}
+			if len(wrapper.Allow) > 0 {
+				r.line("allow %s", strings.Join(wrapper.Allow, " "))
+			}
+			r.close()
+		}
+		r.close()
+	}
+	if server.TrustedProxies != nil {
+		r.line("trusted_proxies %s %s", server.TrustedProxies.Source, strings.Join(server.TrustedProxies.Ranges, " "))
+	}
+	if len(server.ClientIPHeaders) > 0 {
+		r.line("client_ip_headers %s", strings.Join(server.ClientIPHeaders, " "))
+	}
+	if server.ReadTimeout != "" || server.ReadHeaderTimeout != "" || server.WriteTimeout != "" || server.IdleTimeout != "" {
+		r.open("timeouts")
+		for _, t := range [][2]string{
+			{"read_body", string(server.ReadTimeout)},
+			{"read_header", string(server.ReadHeaderTimeout)},
+			{"write", string(server.WriteTimeout)},
+			{"idle", string(server.IdleTimeout)},
+		} {
+			if t[1] != "" {
+				r.line("%s %s", t[0], t[1])
+			}
+		}
+		r.close()
+	}
+	r.close()
+}
+
+// server 输出一个 HTTP 服务器的所有站点块
+// 顶层路由按主机名分组为站点块，没有主机名的路由使用服务器监听地址
+func (r *renderer) server(name string, server *types.HTTPServer) {
+	var sites []*site
+	byKey := make(map[string]*site)
+	siteFor := func(route types.Route) *site {
+		hosts := routeHosts(route)
+		if len(hosts) == 0 {
+			hosts = server.Listen
+		}
+		key := strings.Join(hosts, ", ")
+		s, ok := byKey[key]
+		if !ok {
+			s = &site{addresses: hosts}
+			byKey[key] = s
+			sites = append(sites, s)
+		}
+		return s
+	}
+
+	r.comment("服务器 %s (监听 %s)", name, strings.Join(server.Listen, ", "))
+	for _, route := range server.Routes {
+		if isIPList(route) {
+			r.comment("命名 IP 列表 %s: %s", strings.TrimPrefix(route.ID, "iplist-"), strings.Join(route.Match[0].RemoteIP.Ranges, " "))
+			continue
+		}
+		s := siteFor(route)
+		s.routes = append(s.routes, route)
+	}
+	if server.Errors != nil {
+		for _, route := range server.Errors.Routes {
+			s := siteFor(route)
+			s.errors = append(s.errors, route)
+		}
+	}
+	r.blank()
+
+	for _, s := range sites {
+		r.site(s, server.Logs)
+		r.blank()
+	}
+}
+
+// isIPList 检查路由是否为保存命名 IP 列表的路由 (不会匹配任何请求)
+func isIPList(route types.Route) bool {
+	return strings.HasPrefix(route.ID, "iplist-") && len(route.Match) == 1 &&
+		route.Match[0].Expression == "false" && route.Match[0].RemoteIP != nil
+}
+
+// routeHosts 返回路由所有匹配集中的主机名
+func routeHosts(route types.Route) []string {
+	var hosts []string
+	for _, m := range route.Match {
+		hosts = append(hosts, m.Host...)
+	}
+	return hosts
+}
+
+// site 输出一个站点块
+func (r *renderer) site(s *site, logs *types.ServerLogs) {
+	r.matchers = make(map[string]bool)
+	r.open("%s", strings.Join(s.addresses, ", "))
+
+	if logs != nil {
+		for _, host := range s.addresses {
+			for _, name := range logs.LoggerNames[host] {
+				r.log(name)
+			}
+		}
+	}
+
+	r.routes(s.routes, true)
+
+	if len(s.errors) > 0 {
+		r.open("handle_errors")
+		r.routes(s.errors, true)
+		r.close()
+	}
+	r.close()
+}
+
+// log 输出访问日志指令
+func (r *renderer) log(name string) {
+	log := r.logs[name]
+	if log == nil {
+		r.comment("访问日志 %s 未在 logging 中定义", name)
+		return
+	}
+
+	r.open("log")
+	if w := log.Writer; w != nil {
+		switch w.Output {
+		case "file":
+			if w.RollSizeMB == 0 && w.RollKeep == 0 && w.RollKeepDays == 0 && (w.Roll == nil || *w.Roll) {
+				r.line("output file %s", quote(w.Filename))
+				break
+			}
+			r.open("output file %s", quote(w.Filename))
+			if w.Roll != nil && !*w.Roll {
+				r.line("roll_disabled")
+			}
+			if w.RollSizeMB > 0 {
+				r.line("roll_size %dMiB", w.RollSizeMB)
+			}
+			if w.RollKeep > 0 {
+				r.line("roll_keep %d", w.RollKeep)
+			}
+			if w.RollKeepDays > 0 {
+				r.line("roll_keep_for %dd", w.RollKeepDays)
+			}
+			r.close()
+		case "net":
+			r.line("output net %s", w.Address)
+		default:
+			r.line("output %s", w.Output)
+		}
+	}
+	if log.Encoder != nil && log.Encoder.Format != "" {
+		r.line("format %s", log.Encoder.Format)
+	}
+	if log.Level != "" {
+		r.line("level %s", log.Level)
+	}
+	r.close()
+}
+
+// routes 输出路由列表
+// 只有一个无匹配条件的路由时直接输出其处理器，否则终端路由输出为 handle 块，非终端路由输出为 route 块
+func (r *renderer) routes(routes []types.Route, skipHost bool) {
+	if len(routes) == 1 && matcherEmpty(routes[0].Match, skipHost) {
+		if routes[0].ID != "" {
+			r.comment("@id %s", routes[0].ID)
+		}
+		r.handlers(routes[0].Handle)
+		return
+	}
+
+	for _, route := range routes {
+		if route.ID != "" {
+			r.comment("@id %s", route.ID)
+		}
+		matcher := r.matcher(route, skipHost)
+		block := "route"
+		if route.Terminal || len(routes) == 1 {
+			block = "handle"
+		}
+		if matcher == "" {
+			r.open("%s", block)
+		} else {
+			r.open("%s %s", block, matcher)
+		}
+		r.handlers(route.Handle)
+		r.close()
+	}
+}
+
+// matcherEmpty 检查匹配集是否没有 (主机名以外的) 匹配条件
+func matcherEmpty(match []types.RouteMatch, skipHost bool) bool {
+	for _, m := range match {
+		if !skipHost && len(m.Host) > 0 {
+			return false
+		}
+		m.Host, m.ID = nil, ""
+		if !isEmptyMatch(m) {
+			return false
+		}
+	}
+	return true
+}
+
+// isEmptyMatch 检查匹配集是否为空
+func isEmptyMatch(m types.RouteMatch) bool {
+	return len(m.Host) == 0 && len(m.Path) == 0 && m.File == nil && len(m.Method) == 0 && len(m.Header) == 0 &&
+		m.RemoteIP == nil && m.ClientIP == nil && len(m.Not) == 0 && m.Expression == ""
+}
+
+// matcherNamePattern 命名匹配器中不允许的字符
+var matcherNamePattern = regexp.MustCompile(`[^A-Za-z0-9_-]+`)
+
+// matcher 返回路由的匹配器标记
+// 只有单个路径条件时直接使用路径，否则定义命名匹配器；多个匹配集 (或关系) 只保留第一个并输出注释
+func (r *renderer) matcher(route types.Route, skipHost bool) string {
+	if len(route.Match) == 0 || matcherEmpty(route.Match, skipHost) {
+		return ""
+	}
+	if len(route.Match) > 1 {
+		r.comment("原路由有 %d 个匹配集 (或关系)，此处只保留第一个", len(route.Match))
+	}
+
+	m := route.Match[0]
+	if skipHost {
+		m.Host = nil
+	}
+	m.ID = ""
+	if len(m.Path) == 1 {
+		path := m
+		path.Path = nil
+		if isEmptyMatch(path) {
+			return m.Path[0]
+		}
+	}
+
+	base := route.ID
+	if base == "" && len(m.Host) > 0 {
+		base = m.Host[0]
+	}
+	if base == "" {
+		base = "match"
+	}
+	name := r.matcherName(base)
+	r.open("%s", name)
+	r.matchSet(m)
+	r.close()
+	return name
+}
+
+// matcherName 生成站点块内唯一的命名匹配器名称
+func (r *renderer) matcherName(base string) string {
+	base = strings.Trim(matcherNamePattern.ReplaceAllString(base, "_"), "_")
+	name := base
+	for n := 2; r.matchers[name]; n++ {
+		name = fmt.Sprintf("%s_%d", base, n)
+	}
+	r.matchers[name] = true
+	return "@" + name
+}
+
+// matchSet 输出命名匹配器中的匹配条件
+func (r *renderer) matchSet(m types.RouteMatch) {
+	if len(m.Host) > 0 {
+		r.line("host %s", quoteAll(m.Host))
+	}
+	if len(m.Path) > 0 {
+		r.line("path %s", quoteAll(m.Path))
+	}
+	if len(m.Method) > 0 {
+		r.line("method %s", strings.Join(m.Method, " "))
+	}
+	for _, key := range sortedKeys(m.Header) {
+		for _, value := range m.Header[key] {
+			r.line("header %s %s", key, quote(value))
+		}
+	}
+	if m.RemoteIP != nil {
+		r.line("remote_ip %s", strings.Join(m.RemoteIP.Ranges, " "))
+	}
+	if m.ClientIP != nil {
+		r.line("client_ip %s", strings.Join(m.ClientIP.Ranges, " "))
+	}
+	if m.File != nil {
+		r.open("file")
+		if m.File.Root != "" {
+			r.line("root %s", quote(m.File.Root))
+		}
+		if len(m.File.TryFiles) > 0 {
+			r.line("try_files %s", quoteAll(m.File.TryFiles))
+		}
+		if len(m.File.SplitPath) > 0 {
+			r.line("split_path %s", quoteAll(m.File.SplitPath))
+		}
+		r.close()
+	}
+	// not 的多个匹配集为或关系，取反后等价于多个 not 块同时满足
+	for _, not := range m.Not {
+		not.ID = ""
+		r.open("not")
+		r.matchSet(not)
+		r.close()
+	}
+	if m.Expression != "" {
+		r.line("expression `%s`", m.Expression)
+	}
+}
+
+// handlers 输出处理器链
+func (r *renderer) handlers(handlers []types.Handler) {
+	for _, h := range handlers {
+		if h.ID != "" {
+			r.comment("@id %s", h.ID)
+		}
+		switch h.Handler {
+		case "reverse_proxy":
+			r.reverseProxy(h)
+		case "subroute":
+			r.open("route")
+			r.routes(h.Routes, false)
+			r.close()
+		case "file_server":
+			r.fileServer(h)
+		case "static_response":
+			r.staticResponse(h)
+		case "headers":
+			r.headerOps("request_header", h.Request, false)
+			if h.Response != nil {
+				r.headerOps("header", &h.Response.HeaderOps, h.Response.Deferred)
+			}
+		case "encode":
+			r.encode(h)
+		case "authentication":
+			r.basicAuth(h)
+		case "rewrite":
+			r.rewrite(h)
+		case "request_body":
+			r.open("request_body")
+			r.line("max_size %d", h.MaxSize)
+			r.close()
+		case "rate_limit":
+			r.rateLimit(h)
+		case "vars":
+			if h.Root == "" {
+				r.comment("无法转换的处理器 vars")
+				break
+			}
+			r.line("root * %s", quote(h.Root))
+		default:
+			data, _ := json.Marshal(h)
+			r.comment("无法转换的处理器 %s: %s", h.Handler, data)
+		}
+	}
+}
+
+// headerLines 将头部操作转换为指令参数 (设置、追加 "+"、删除 "-"、替换)
+func headerLines(ops *types.HeaderOps) []string {
+	var lines []string
+	for _, key := range sortedKeys(ops.Set) {
+		for i, value := range ops.Set[key] {
+			prefix := ""
+			if i > 0 {
+				prefix = "+"
+			}
+			lines = append(lines, fmt.Sprintf("%s%s %s", prefix, key, quote(value)))
+		}
+	}
+	for _, key := range sortedKeys(ops.Add) {
+		for _, value := range ops.Add[key] {
+			lines = append(lines, fmt.Sprintf("+%s %s", key, quote(value)))
+		}
+	}
+	for _, key := range ops.Delete {
+		lines = append(lines, "-"+key)
+	}
+	replaceKeys := make([]string, 0, len(ops.Replace))
+	for key := range ops.Replace {
+		replaceKeys = append(replaceKeys, key)
+	}
+	sort.Strings(replaceKeys)
+	for _, key := range replaceKeys {
+		for _, rep := range ops.Replace[key] {
+			search := rep.Search
+			if rep.SearchRegexp != "" {
+				search = rep.SearchRegexp
+			}
+			lines = append(lines, fmt.Sprintf("%s %s %s", key, quote(search), quote(rep.Replace)))
+		}
+	}
+	return lines
+}
+
+// headerOps 输出 header 或 request_header 指令
+// 延迟执行的响应头操作使用带 defer 的块
+func (r *renderer) headerOps(directive string, ops *types.HeaderOps, deferred bool) {
+	if ops == nil {
+		return
+	}
+	lines := headerLines(ops)
+	if deferred && len(lines) > 0 {
+		r.open("%s", directive)
+		r.line("defer")
+		for _, line := range lines {
+			r.line("%s", line)
+		}
+		r.close()
+		return
+	}
+	for _, line := range lines {
+		r.line("%s %s", directive, line)
+	}
+}
+
+// reverseProxy 输出 reverse_proxy 指令
+func (r *renderer) reverseProxy(h types.Handler) {
+	dials := make([]string, len(h.Upstreams))
+	for i, upstream := range h.Upstreams {
+		dials[i] = upstream.Dial
+	}
+
+	// 子指令先输出到单独的渲染器，没有子指令时输出单行
+	sub := &renderer{depth: r.depth + 1, logs: r.logs, matchers: r.matchers, global: r.global}
+	sub.proxyOptions(h)
+	r.global = sub.global
+	if sub.b.Len() == 0 {
+		r.line("reverse_proxy %s", strings.Join(dials, " "))
+		return
+	}
+	r.line("reverse_proxy %s {", strings.Join(dials, " "))
+	r.b.WriteString(sub.b.String())
+	r.line("}")
+}
+
+// proxyOptions 输出 reverse_proxy 的子指令
+func (r *renderer) proxyOptions(h types.Handler) {
+	if lb := h.LoadBalancing; lb != nil {
+		if p := lb.SelectionPolicy; p != nil {
+			weights := make([]string, len(p.Weights))
+			for i, w := range p.Weights {
+				weights[i] = strconv.Itoa(w)
+			}
+			r.line("%s", strings.TrimSpace("lb_policy "+p.Policy+" "+strings.Join(weights, " ")))
+		}
+		if lb.Retries > 0 {
+			r.line("lb_retries %d", lb.Retries)
+		}
+		if lb.TryDuration != "" {
+			r.line("lb_try_duration %s", lb.TryDuration)
+		}
+		if lb.TryInterval != "" {
+			r.line("lb_try_interval %s", lb.TryInterval)
+		}
+	}
+	for _, d := range [][2]string{
+		{"flush_interval", string(h.FlushInterval)},
+		{"stream_timeout", string(h.StreamTimeout)},
+		{"stream_close_delay", string(h.StreamCloseDelay)},
+	} {
+		if d[1] != "" {
+			r.line("%s %s", d[0], d[1])
+		}
+	}
+	if h.RequestBuffers != 0 {
+		r.line("request_buffers %s", bufferSize(h.RequestBuffers))
+	}
+	if h.ResponseBuffers != 0 {
+		r.line("response_buffers %s", bufferSize(h.ResponseBuffers))
+	}
+	if h.UpstreamHeaders != nil {
+		r.headerOps("header_up", h.UpstreamHeaders.Request, false)
+		if h.UpstreamHeaders.Response != nil {
+			r.headerOps("header_down", &h.UpstreamHeaders.Response.HeaderOps, false)
+		}
+	}
+	if h.Rewrite != nil {
+		if h.Rewrite.Method != "" {
+			r.line("method %s", h.Rewrite.Method)
+		}
+		if h.Rewrite.URI != "" {
+			r.line("rewrite %s", quote(h.Rewrite.URI))
+		}
+	}
+	if h.Transport != nil {
+		r.transport(h.Transport)
+	}
+	for _, resp := range h.HandleResponse {
+		r.handleResponse(resp)
+	}
+}
+
+// bufferSize 格式化缓冲区大小 (-1 表示不限制)
+func bufferSize(size int64) string {
+	if size < 0 {
+		return "unlimited"
+	}
+	return strconv.FormatInt(size, 10)
+}
+
+// transport 输出 reverse_proxy 的 transport 子指令
+func (r *renderer) transport(t *types.Transport) {
+	if t.Protocol == "fastcgi" {
+		r.open("transport fastcgi")
+		if t.Root != "" {
+			r.line("root %s", quote(t.Root))
+		}
+		if len(t.SplitPath) > 0 {
+			r.line("split %s", quoteAll(t.SplitPath))
+		}
+		keys := make([]string, 0, len(t.Env))
+		for key := range t.Env {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			r.line("env %s %s", key, quote(t.Env[key]))
+		}
+		r.close()
+		return
+	}
+
+	r.open("transport %s", t.Protocol)
+	if t.TLS != nil {
+		r.line("tls")
+		if t.TLS.ServerName != "" {
+			r.line("tls_server_name %s", t.TLS.ServerName)
+		}
+		if t.TLS.InsecureSkipVerify {
+			r.line("tls_insecure_skip_verify")
+		}
+		if t.TLS.CA != nil && len(t.TLS.CA.PEMFiles) > 0 {
+			r.line("tls_trust_pool file %s", quoteAll(t.TLS.CA.PEMFiles))
+		}
+	}
+	if len(t.Versions) > 0 {
+		r.line("versions %s", strings.Join(t.Versions, " "))
+	}
+	if t.DialTimeout != "" {
+		r.line("dial_timeout %s", t.DialTimeout)
+	}
+	if t.ResponseHeaderTimeout != "" {
+		r.line("response_header_timeout %s", t.ResponseHeaderTimeout)
+	}
+	if ka := t.KeepAlive; ka != nil {
+		if ka.Enabled != nil && !*ka.Enabled {
+			r.line("keepalive off")
+		} else if ka.IdleConnTimeout != "" {
+			r.line("keepalive %s", ka.IdleConnTimeout)
+		}
+		if ka.ProbeInterval != "" {
+			r.line("keepalive_interval %s", ka.ProbeInterval)
+		}
+		if ka.MaxIdleConnsPerHost > 0 {
+			r.line("keepalive_idle_conns_per_host %d", ka.MaxIdleConnsPerHost)
+		}
+		if ka.MaxIdleConns > 0 {
+			r.line("keepalive_idle_conns %d", ka.MaxIdleConns)
+		}
+	}
+	if t.ReadBufferSize > 0 {
+		r.line("read_buffer %d", t.ReadBufferSize)
+	}
+	if t.WriteBufferSize > 0 {
+		r.line("write_buffer %d", t.WriteBufferSize)
+	}
+	if t.Compression != nil && !*t.Compression {
+		r.line("compression off")
+	}
+	r.close()
+}
+
+// handleResponse 输出 reverse_proxy 的 handle_response 子指令
+func (r *renderer) handleResponse(resp types.ResponseHandler) {
+	matcher := ""
+	if resp.Match != nil {
+		matcher = r.matcherName("response")
+		r.open("%s", matcher)
+		if len(resp.Match.StatusCode) > 0 {
+			codes := make([]string, len(resp.Match.StatusCode))
+			for j, code := range resp.Match.StatusCode {
+				codes[j] = strconv.Itoa(code)
+				if code < 10 {
+					codes[j] += "xx"
+				}
+			}
+			r.line("status %s", strings.Join(codes, " "))
+		}
+		for _, key := range sortedKeys(resp.Match.Headers) {
+			for _, value := range resp.Match.Headers[key] {
+				r.line("header %s %s", key, quote(value))
+			}
+		}
+		r.close()
+		matcher = " " + matcher
+	}
+
+	r.open("handle_response%s", matcher)
+	if resp.StatusCode != "" {
+		r.comment("原配置覆盖响应状态码为 %s", resp.StatusCode)
+	}
+	r.routes(resp.Routes, false)
+	r.close()
+}
+
+// fileServer 输出 file_server 指令
+func (r *renderer) fileServer(h types.Handler) {
+	if h.Root == "" && len(h.Hide) == 0 && len(h.IndexNames) == 0 && len(h.Precompressed) == 0 {
+		if h.Browse != nil && h.Browse.TemplateFile == "" {
+			r.line("file_server browse")
+			return
+		}
+		if h.Browse == nil {
+			r.line("file_server")
+			return
+		}
+	}
+
+	r.open("file_server")
+	if h.Root != "" {
+		r.line("root %s", quote(h.Root))
+	}
+	if len(h.Hide) > 0 {
+		r.line("hide %s", quoteAll(h.Hide))
+	}
+	if len(h.IndexNames) > 0 {
+		r.line("index %s", quoteAll(h.IndexNames))
+	}
+	if h.Browse != nil {
+		if h.Browse.TemplateFile != "" {
+			r.line("browse %s", quote(h.Browse.TemplateFile))
+		} else {
+			r.line("browse")
+		}
+	}
+	if len(h.Precompressed) > 0 {
+		formats := h.PrecompressedOrder
+		if len(formats) == 0 {
+			for format := range h.Precompressed {
+				formats = append(formats, format)
+			}
+			sort.Strings(formats)
+		}
+		r.line("precompressed %s", strings.Join(formats, " "))
+	}
+	r.close()
+}
+
+// staticResponse 输出 static_response 对应的 redir 或 respond 指令
+func (r *renderer) staticResponse(h types.Handler) {
+	status := string(h.StatusCode)
+	code, _ := strconv.Atoi(status)
+	if location := h.Headers["Location"]; code >= 300 && code < 400 && len(location) == 1 && h.Body == "" {
+		r.line("redir %s %s", quote(location[0]), status)
+		for _, key := range sortedKeys(h.Headers) {
+			if key != "Location" {
+				r.comment("重定向响应头 %s 无法转换", key)
+			}
+		}
+		return
+	}
+
+	for _, key := range sortedKeys(h.Headers) {
+		for i, value := range h.Headers[key] {
+			prefix := ""
+			if i > 0 {
+				prefix = "+"
+			}
+			r.line("header %s%s %s", prefix, key, quote(value))
+		}
+	}
+
+	args := []string{"respond"}
+	if strings.Contains(h.Body, "\n") {
+		r.heredoc(h.Body, status)
+		return
+	}
+	if h.Body != "" {
+		args = append(args, quote(h.Body))
+	}
+	if status != "" {
+		args = append(args, status)
+	}
+	r.line("%s", strings.Join(args, " "))
+}
+
+// heredoc 使用 heredoc 输出多行响应内容
+func (r *renderer) heredoc(body, status string) {
+	marker := "BODY"
+	for strings.Contains(body, marker) {
+		marker += "_"
+	}
+	r.line("respond <<%s", marker)
+	prefix := strings.Repeat(indent, r.depth+1)
+	for _, line := range strings.Split(strings.TrimRight(body, "\n"), "\n") {
+		if line == "" {
+			r.b.WriteString("\n")
+			continue
+		}
+		r.b.WriteString(prefix + line + "\n")
+	}
+	r.b.WriteString(prefix + marker)
+	if status != "" {
+		r.b.WriteString(" " + status)
+	}
+	r.b.WriteString("\n")
+}
+
+// encode 输出 encode 指令
+func (r *renderer) encode(h types.Handler) {
+	formats := h.Prefer
+	if len(formats) == 0 {
+		for format := range h.Encodings {
+			formats = append(formats, format)
+		}
+		sort.Strings(formats)
+	}
+
+	simple := h.MinimumLength == 0 && h.Match == nil
+	for _, enc := range h.Encodings {
+		if enc.Level != 0 {
+			simple = false
+		}
+	}
+	if simple {
+		r.line("encode %s", strings.Join(formats, " "))
+		return
+	}
+
+	r.open("encode")
+	for _, format := range formats {
+		if level := h.Encodings[format].Level; level != 0 {
+			r.line("%s %d", format, level)
+		} else {
+			r.line("%s", format)
+		}
+	}
+	if h.MinimumLength > 0 {
+		r.line("minimum_length %d", h.MinimumLength)
+	}
+	if h.Match != nil && len(h.Match.Headers) > 0 {
+		r.open("match")
+		for _, key := range sortedKeys(h.Match.Headers) {
+			for _, value := range h.Match.Headers[key] {
+				r.line("header %s %s", key, quote(value))
+			}
+		}
+		r.close()
+	}
+	r.close()
+}
+
+// basicAuth 输出 basic_auth 指令
+func (r *renderer) basicAuth(h types.Handler) {
+	if h.Providers == nil || h.Providers.HTTPBasic == nil {
+		r.comment("无法转换的认证处理器")
+		return
+	}
+	auth := h.Providers.HTTPBasic
+
+	args := "basic_auth"
+	if auth.Hash != nil || auth.Realm != "" {
+		algorithm := "bcrypt"
+		if auth.Hash != nil {
+			algorithm = auth.Hash.Algorithm
+		}
+		args += " " + algorithm
+		if auth.Realm != "" {
+			args += " " + quote(auth.Realm)
+		}
+	}
+
+	r.open("%s", args)
+	for _, account := range auth.Accounts {
+		r.line("%s %s", quote(account.Username), account.Password)
+	}
+	r.close()
+}
+
+// rewrite 输出 rewrite 对应的 rewrite、uri 和 method 指令
+func (r *renderer) rewrite(h types.Handler) {
+	if h.Method != "" {
+		r.line("method %s", h.Method)
+	}
+	if h.URI != "" {
+		r.line("rewrite %s", quote(h.URI))
+	}
+	if h.StripPathPrefix != "" {
+		r.line("uri strip_prefix %s", quote(h.StripPathPrefix))
+	}
+	if h.StripPathSuffix != "" {
+		r.line("uri strip_suffix %s", quote(h.StripPathSuffix))
+	}
+	for _, sub := range h.URISubstring {
+		if sub.Limit > 0 {
+			r.line("uri replace %s %s %d", quote(sub.Find), quote(sub.Replace), sub.Limit)
+		} else {
+			r.line("uri replace %s %s", quote(sub.Find), quote(sub.Replace))
+		}
+	}
+	for _, re := range h.PathRegexp {
+		r.line("uri path_regexp %s %s", quote(re.Find), quote(re.Replace))
+	}
+}
+
+// rateLimitOrder rate_limit 不是标准指令，需要在全局选项中指定顺序
+const rateLimitOrder = "order rate_limit before basic_auth"
+
+// rateLimit 输出 rate_limit 指令 (需要 caddy-ratelimit 模块)
+func (r *renderer) rateLimit(h types.Handler) {
+	found := false
+	for _, line := range r.global {
+		found = found || line == rateLimitOrder
+	}
+	if !found {
+		r.global = append(r.global, rateLimitOrder)
+	}
+
+	zones := make([]string, 0, len(h.RateLimits))
+	for zone := range h.RateLimits {
+		zones = append(zones, zone)
+	}
+	sort.Strings(zones)
+
+	r.open("rate_limit")
+	for _, name := range zones {
+		zone := h.RateLimits[name]
+		r.open("zone %s", name)
+		r.line("key %s", quote(zone.Key))
+		r.line("events %d", zone.MaxEvents)
+		r.line("window %s", zone.Window)
+		r.close()
+	}
+	r.close()
+}
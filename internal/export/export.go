@@ -0,0 +1,88 @@
+package export
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+
+	"gopkg.in/yaml.v3"
+)
+
+// 导出格式
+const (
+	FormatCaddyfile = "caddyfile"
+	FormatJSON      = "json"
+	FormatYAML      = "yaml"
+)
+
+// Formats 支持的导出格式
+var Formats = []string{FormatCaddyfile, FormatJSON, FormatYAML}
+
+// Export 将 Caddy JSON 配置转换为指定格式
+func Export(data []byte, format string) ([]byte, error) {
+	switch format {
+	case FormatCaddyfile, "":
+		out, err := Caddyfile(data)
+		return []byte(out), err
+	case FormatJSON:
+		return JSON(data)
+	case FormatYAML:
+		return YAML(data)
+	default:
+		return nil, fmt.Errorf("不支持的导出格式: %s (可选: caddyfile, json, yaml)", format)
+	}
+}
+
+// JSON 格式化 JSON 配置 (两个空格缩进)
+func JSON(data []byte) ([]byte, error) {
+	var out bytes.Buffer
+	if err := json.Indent(&out, data, "", "  "); err != nil {
+		return nil, fmt.Errorf("格式化 JSON 失败: %w", err)
+	}
+	out.WriteByte('\n')
+	return out.Bytes(), nil
+}
+
+// YAML 将 JSON 配置转换为 YAML
+// 整数保持原样输出，不会转换为浮点数形式 (如 1048576 而不是 1.048576e+06)
+func YAML(data []byte) ([]byte, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var cfg interface{}
+	if err := decoder.Decode(&cfg); err != nil {
+		return nil, fmt.Errorf("解析配置失败: %w", err)
+	}
+
+	var out bytes.Buffer
+	encoder := yaml.NewEncoder(&out)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(convertNumbers(cfg)); err != nil {
+		return nil, fmt.Errorf("转换 YAML 失败: %w", err)
+	}
+	if err := encoder.Close(); err != nil {
+		return nil, fmt.Errorf("转换 YAML 失败: %w", err)
+	}
+	return out.Bytes(), nil
+}
+
+// convertNumbers 将 json.Number 转换为整数或浮点数
+func convertNumbers(v interface{}) interface{} {
+	switch val := v.(type) {
+	case map[string]interface{}:
+		for key, child := range val {
+			val[key] = convertNumbers(child)
+		}
+	case []interface{}:
+		for i, child := range val {
+			val[i] = convertNumbers(child)
+		}
+	case json.Number:
+		if n, err := val.Int64(); err == nil {
+			return n
+		}
+		if f, err := val.Float64(); err == nil {
+			return f
+		}
+	}
+	return v
+}
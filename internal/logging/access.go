@@ -0,0 +1,229 @@
+package logging
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// AccessEntry Caddy JSON 访问日志条目
+type AccessEntry struct {
+	Level     string        `json:"level"`
+	Timestamp float64       `json:"ts"`
+	Logger    string        `json:"logger"`
+	Message   string        `json:"msg"`
+	Request   AccessRequest `json:"request"`
+	BytesRead int64         `json:"bytes_read"`
+	Duration  float64       `json:"duration"` // 处理耗时 (秒)
+	Size      int64         `json:"size"`
+	Status    int           `json:"status"`
+}
+
+// AccessRequest 访问日志中的请求信息
+type AccessRequest struct {
+	RemoteIP string `json:"remote_ip"`
+	ClientIP string `json:"client_ip"`
+	Proto    string `json:"proto"`
+	Method   string `json:"method"`
+	Host     string `json:"host"`
+	URI      string `json:"uri"`
+}
+
+// ParseAccessEntry 解析一行 JSON 访问日志，非访问日志条目返回 false
+func ParseAccessEntry(line []byte) (AccessEntry, bool) {
+	var entry AccessEntry
+	if err := json.Unmarshal(line, &entry); err != nil {
+		return AccessEntry{}, false
+	}
+	if !strings.HasPrefix(entry.Logger, strings.TrimSuffix(AccessLogPrefix, ".")) || entry.Request.Method == "" {
+		return AccessEntry{}, false
+	}
+	return entry, true
+}
+
+// Time 返回条目的时间
+func (e AccessEntry) Time() time.Time {
+	sec, frac := math.Modf(e.Timestamp)
+	return time.Unix(int64(sec), int64(frac*1e9))
+}
+
+// Latency 返回请求处理耗时
+func (e AccessEntry) Latency() time.Duration {
+	return time.Duration(e.Duration * float64(time.Second))
+}
+
+// Path 返回不含查询参数的请求路径
+func (e AccessEntry) Path() string {
+	if i := strings.IndexByte(e.Request.URI, '?'); i >= 0 {
+		return e.Request.URI[:i]
+	}
+	return e.Request.URI
+}
+
+// String 格式化为单行可读文本
+func (e AccessEntry) String() string {
+	clientIP := e.Request.ClientIP
+	if clientIP == "" {
+		clientIP = e.Request.RemoteIP
+	}
+	return fmt.Sprintf("%s %3d %-6s %s%s %s %s %s",
+		e.Time().Format("2006-01-02 15:04:05"),
+		e.Status,
+		e.Request.Method,
+		e.Request.Host,
+		e.Request.URI,
+		e.Latency().Round(time.Microsecond),
+		formatBytes(e.Size),
+		clientIP,
+	)
+}
+
+// formatBytes 将字节数格式化为可读大小
+func formatBytes(n int64) string {
+	switch {
+	case n >= 1<<20:
+		return fmt.Sprintf("%.1fMB", float64(n)/(1<<20))
+	case n >= 1<<10:
+		return fmt.Sprintf("%.1fKB", float64(n)/(1<<10))
+	default:
+		return fmt.Sprintf("%dB", n)
+	}
+}
+
+// StatusFilter 状态码过滤条件，如 "404"、"5xx" 或 "404,5xx"
+type StatusFilter []string
+
+// ParseStatusFilter 解析状态码过滤条件
+func ParseStatusFilter(s string) (StatusFilter, error) {
+	var filter StatusFilter
+	for _, part := range strings.Split(s, ",") {
+		part = strings.ToLower(strings.TrimSpace(part))
+		if part == "" {
+			continue
+		}
+		if len(part) != 3 {
+			return nil, fmt.Errorf("无效的状态码过滤条件: %s", part)
+		}
+		if strings.HasSuffix(part, "xx") {
+			if part[0] < '1' || part[0] > '5' {
+				return nil, fmt.Errorf("无效的状态码过滤条件: %s", part)
+			}
+		} else if code, err := strconv.Atoi(part); err != nil || code < 100 || code > 599 {
+			return nil, fmt.Errorf("无效的状态码过滤条件: %s", part)
+		}
+		filter = append(filter, part)
+	}
+	return filter, nil
+}
+
+// Match 检查状态码是否匹配任一条件
+func (f StatusFilter) Match(status int) bool {
+	if len(f) == 0 {
+		return true
+	}
+	code := strconv.Itoa(status)
+	for _, pattern := range f {
+		if pattern == code || (strings.HasSuffix(pattern, "xx") && pattern[0] == code[0]) {
+			return true
+		}
+	}
+	return false
+}
+
+// AccessFilter 访问日志过滤条件，零值字段表示不过滤
+type AccessFilter struct {
+	Host       string        // 主机名
+	Status     StatusFilter  // 状态码
+	PathPrefix string        // 请求路径前缀
+	MinLatency time.Duration // 最小处理耗时
+	Since      time.Time     // 起始时间
+}
+
+// Match 检查条目是否满足所有过滤条件
+func (f AccessFilter) Match(e AccessEntry) bool {
+	if f.Host != "" && !strings.EqualFold(f.Host, e.Request.Host) {
+		return false
+	}
+	if !f.Status.Match(e.Status) {
+		return false
+	}
+	if f.PathPrefix != "" && !strings.HasPrefix(e.Path(), f.PathPrefix) {
+		return false
+	}
+	if f.MinLatency > 0 && e.Latency() < f.MinLatency {
+		return false
+	}
+	if !f.Since.IsZero() && e.Time().Before(f.Since) {
+		return false
+	}
+	return true
+}
+
+// PathCount 路径及其请求次数
+type PathCount struct {
+	Path  string
+	Count int
+}
+
+// AccessSummary 访问日志统计
+type AccessSummary struct {
+	Total        int
+	ClientErrors int // 4xx
+	ServerErrors int // 5xx
+	TotalLatency time.Duration
+	paths        map[string]int
+}
+
+// Add 将条目计入统计
+func (s *AccessSummary) Add(e AccessEntry) {
+	if s.paths == nil {
+		s.paths = make(map[string]int)
+	}
+	s.Total++
+	s.TotalLatency += e.Latency()
+	s.paths[e.Path()]++
+	switch {
+	case e.Status >= 500:
+		s.ServerErrors++
+	case e.Status >= 400:
+		s.ClientErrors++
+	}
+}
+
+// ErrorRate 返回 5xx 响应所占比例
+func (s *AccessSummary) ErrorRate() float64 {
+	if s.Total == 0 {
+		return 0
+	}
+	return float64(s.ServerErrors) / float64(s.Total)
+}
+
+// AverageLatency 返回平均处理耗时
+func (s *AccessSummary) AverageLatency() time.Duration {
+	if s.Total == 0 {
+		return 0
+	}
+	return s.TotalLatency / time.Duration(s.Total)
+}
+
+// TopPaths 返回请求次数最多的 n 个路径
+func (s *AccessSummary) TopPaths(n int) []PathCount {
+	paths := make([]PathCount, 0, len(s.paths))
+	for path, count := range s.paths {
+		paths = append(paths, PathCount{Path: path, Count: count})
+	}
+	sort.Slice(paths, func(i, j int) bool {
+		if paths[i].Count != paths[j].Count {
+			return paths[i].Count > paths[j].Count
+		}
+		return paths[i].Path < paths[j].Path
+	})
+	if n > 0 && len(paths) > n {
+		paths = paths[:n]
+	}
+	return paths
+}
@@ -0,0 +1,267 @@
+package logging
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/youfun/fastcaddy/internal/api"
+	"github.com/youfun/fastcaddy/internal/config"
+	"github.com/youfun/fastcaddy/internal/routes"
+	"github.com/youfun/fastcaddy/pkg/types"
+)
+
+// 常量定义 - 日志配置路径
+const (
+	LogsPath         = "/logging/logs"
+	DefaultLogName   = "default"          // Caddy 默认日志名称
+	AccessLogPrefix  = "http.log.access." // 访问日志记录器名称前缀
+	accessNamePrefix = "access_"
+)
+
+// Manager 日志管理器 - 处理命名日志和访问日志配置
+type Manager struct {
+	client        *api.Client
+	configManager *config.Manager
+}
+
+// NewManager 创建新的日志管理器
+func NewManager() *Manager {
+	return &Manager{
+		client:        api.NewClient(),
+		configManager: config.NewManager(),
+	}
+}
+
+// NewManagerWithClient 使用指定的 API 客户端创建日志管理器
+func NewManagerWithClient(client *api.Client) *Manager {
+	return &Manager{
+		client:        client,
+		configManager: config.NewManagerWithClient(client),
+	}
+}
+
+// AccessLogOptions 访问日志选项
+type AccessLogOptions struct {
+	Name   string          // 日志名称 (默认根据主机名生成)
+	Writer types.LogWriter // 日志输出 (默认: stdout)
+	Format string          // 编码格式 "json" 或 "console" (默认: json)
+	Level  string          // 最低日志级别
+}
+
+// AccessLogName 根据主机名生成访问日志名称 (如 api.example.com -> access_api_example_com)
+func AccessLogName(host string) string {
+	name := strings.Replace(host, "*", "wildcard", -1)
+	name = strings.NewReplacer(".", "_", ":", "_", "/", "_").Replace(name)
+	return accessNamePrefix + name
+}
+
+// validateWriter 检查日志输出配置是否完整
+func validateWriter(w types.LogWriter) error {
+	switch w.Output {
+	case "stdout", "stderr", "discard":
+	case "file":
+		if w.Filename == "" {
+			return fmt.Errorf("file 输出需要指定文件路径")
+		}
+	case "net":
+		if w.Address == "" {
+			return fmt.Errorf("net 输出需要指定网络地址")
+		}
+	default:
+		return fmt.Errorf("不支持的日志输出类型: %s", w.Output)
+	}
+	return nil
+}
+
+// SetLog 创建或替换命名日志
+func (m *Manager) SetLog(name string, log types.CustomLog) error {
+	if name == "" {
+		return fmt.Errorf("日志名称不能为空")
+	}
+	if log.Writer != nil {
+		if err := validateWriter(*log.Writer); err != nil {
+			return err
+		}
+	}
+
+	// 只补全缺失的层级，保留已有的其他日志
+	if err := m.configManager.EnsurePath(LogsPath); err != nil {
+		return fmt.Errorf("初始化日志配置失败: %w", err)
+	}
+	return m.client.PutConfig(log, LogsPath+"/"+name, "POST")
+}
+
+// DeleteLog 删除命名日志
+func (m *Manager) DeleteLog(name string) error {
+	return m.client.DeleteConfig(LogsPath + "/" + name)
+}
+
+// GetAccessLogs 获取服务器的访问日志配置，未启用时返回 nil
+func (m *Manager) GetAccessLogs(serverName string) (*types.ServerLogs, error) {
+	logsPath := fmt.Sprintf("%s/%s/logs", routes.ServersPath, serverName)
+	if !m.client.HasValue(logsPath) {
+		return nil, nil
+	}
+
+	var logs types.ServerLogs
+	if err := m.client.GetConfigInto(logsPath, &logs); err != nil {
+		return nil, fmt.Errorf("获取服务器 %s 访问日志配置失败: %w", serverName, err)
+	}
+	return &logs, nil
+}
+
+// EnableAccessLog 为主机启用访问日志
+// 创建只包含该主机访问日志的命名日志，并在服务器的 logger_names 中建立映射
+func (m *Manager) EnableAccessLog(serverName, host string, opts AccessLogOptions) error {
+	if host == "" {
+		return fmt.Errorf("主机名不能为空")
+	}
+	serverPath := fmt.Sprintf("%s/%s", routes.ServersPath, serverName)
+	if !m.client.HasValue(serverPath) {
+		return fmt.Errorf("服务器 %s 不存在", serverName)
+	}
+
+	name := opts.Name
+	if name == "" {
+		name = AccessLogName(host)
+	}
+	writer := opts.Writer
+	if writer.Output == "" {
+		writer.Output = "stdout"
+	}
+	format := opts.Format
+	if format == "" {
+		format = "json"
+	}
+	if format != "json" && format != "console" {
+		return fmt.Errorf("不支持的日志格式: %s", format)
+	}
+
+	log := types.CustomLog{
+		Writer:  &writer,
+		Encoder: &types.LogEncoder{Format: format},
+		Level:   opts.Level,
+		Include: []string{AccessLogPrefix + name},
+	}
+	if err := m.SetLog(name, log); err != nil {
+		return err
+	}
+	// 与 Caddyfile 的 log 指令一致，避免访问日志同时写入默认日志
+	if err := m.excludeFromDefault(AccessLogPrefix+name, true); err != nil {
+		return err
+	}
+
+	// 保留服务器已有的日志映射和其他设置
+	logs, err := m.GetAccessLogs(serverName)
+	if err != nil {
+		return err
+	}
+	if logs == nil {
+		logs = &types.ServerLogs{}
+	}
+	if logs.LoggerNames == nil {
+		logs.LoggerNames = make(map[string][]string)
+	}
+	logs.LoggerNames[host] = []string{name}
+
+	return m.client.PutConfig(logs, serverPath+"/logs", "POST")
+}
+
+// DisableAccessLog 关闭主机的访问日志
+// 移除 logger_names 映射，并删除不再被其他主机引用的命名日志
+func (m *Manager) DisableAccessLog(serverName, host string) error {
+	logs, err := m.GetAccessLogs(serverName)
+	if err != nil {
+		return err
+	}
+	if logs == nil {
+		return fmt.Errorf("服务器 %s 未启用访问日志", serverName)
+	}
+	names, ok := logs.LoggerNames[host]
+	if !ok {
+		return fmt.Errorf("主机 %s 未启用访问日志", host)
+	}
+	delete(logs.LoggerNames, host)
+
+	logsPath := fmt.Sprintf("%s/%s/logs", routes.ServersPath, serverName)
+	// 没有其他设置时移除 logs，避免为所有主机记录访问日志
+	if len(logs.LoggerNames) == 0 && logs.DefaultLoggerName == "" && len(logs.SkipHosts) == 0 && !logs.SkipUnmappedHosts {
+		err = m.client.DeleteConfig(logsPath)
+	} else {
+		err = m.client.PutConfig(logs, logsPath, "PATCH")
+	}
+	if err != nil {
+		return fmt.Errorf("更新服务器 %s 访问日志配置失败: %w", serverName, err)
+	}
+
+	// 删除不再被引用的命名日志
+	for _, name := range names {
+		if referenced(logs, name) || !m.client.HasValue(LogsPath+"/"+name) {
+			continue
+		}
+		if err := m.DeleteLog(name); err != nil {
+			return fmt.Errorf("删除日志 %s 失败: %w", name, err)
+		}
+		if err := m.excludeFromDefault(AccessLogPrefix+name, false); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// excludeFromDefault 在默认日志的 exclude 列表中添加或移除日志记录器
+// 默认日志不存在时创建只包含 exclude 的默认日志 (其余设置沿用 Caddy 默认值)；
+// 移除后默认日志不再有任何设置时将其删除
+func (m *Manager) excludeFromDefault(logger string, exclude bool) error {
+	path := LogsPath + "/" + DefaultLogName
+	var log types.CustomLog
+	if m.client.HasValue(path) {
+		if err := m.client.GetConfigInto(path, &log); err != nil {
+			return fmt.Errorf("获取默认日志配置失败: %w", err)
+		}
+	} else if !exclude {
+		return nil
+	}
+
+	index := -1
+	for i, name := range log.Exclude {
+		if name == logger {
+			index = i
+			break
+		}
+	}
+	switch {
+	case exclude && index < 0:
+		log.Exclude = append(log.Exclude, logger)
+	case !exclude && index >= 0:
+		log.Exclude = append(log.Exclude[:index], log.Exclude[index+1:]...)
+	default:
+		return nil
+	}
+
+	var err error
+	if log.Writer == nil && log.Encoder == nil && log.Level == "" && len(log.Include) == 0 && len(log.Exclude) == 0 && len(log.Extra) == 0 {
+		err = m.DeleteLog(DefaultLogName)
+	} else {
+		err = m.SetLog(DefaultLogName, log)
+	}
+	if err != nil {
+		return fmt.Errorf("更新默认日志配置失败: %w", err)
+	}
+	return nil
+}
+
+// referenced 检查日志名称是否仍被服务器配置引用
+func referenced(logs *types.ServerLogs, name string) bool {
+	if logs.DefaultLoggerName == name {
+		return true
+	}
+	for _, names := range logs.LoggerNames {
+		for _, n := range names {
+			if n == name {
+				return true
+			}
+		}
+	}
+	return false
+}
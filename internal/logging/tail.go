@@ -0,0 +1,150 @@
+package logging
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/youfun/fastcaddy/pkg/types"
+)
+
+// tailPollInterval 跟踪日志文件时的轮询间隔
+const tailPollInterval = 500 * time.Millisecond
+
+// TailOptions 读取访问日志的选项
+type TailOptions struct {
+	Follow    bool // 是否持续跟踪新写入的日志
+	FromStart bool // 是否从文件开头读取 (否则只读取新写入的内容)
+}
+
+// AccessLogFile 根据服务器的 logger_names 映射查找主机访问日志的文件路径
+func (m *Manager) AccessLogFile(serverName, host string) (string, error) {
+	logs, err := m.GetAccessLogs(serverName)
+	if err != nil {
+		return "", err
+	}
+	if logs == nil || len(logs.LoggerNames[host]) == 0 {
+		return "", fmt.Errorf("主机 %s 未启用访问日志", host)
+	}
+
+	for _, name := range logs.LoggerNames[host] {
+		var log types.CustomLog
+		if err := m.client.GetConfigInto(LogsPath+"/"+name, &log); err != nil {
+			continue
+		}
+		if log.Writer != nil && log.Writer.Output == "file" {
+			return log.Writer.Filename, nil
+		}
+	}
+	return "", fmt.Errorf("主机 %s 的访问日志未写入文件", host)
+}
+
+// TailAccessLog 读取 JSON 访问日志文件，对每个访问日志条目调用 fn
+// 跟踪模式下会处理日志滚动 (文件被替换或截断)，直到 ctx 被取消
+func TailAccessLog(ctx context.Context, filename string, opts TailOptions, fn func(AccessEntry)) error {
+	file, err := os.Open(filename)
+	if err != nil {
+		return fmt.Errorf("打开日志文件失败: %w", err)
+	}
+	defer func() { file.Close() }()
+
+	if !opts.FromStart {
+		if _, err := file.Seek(0, io.SeekEnd); err != nil {
+			return fmt.Errorf("定位日志文件失败: %w", err)
+		}
+	}
+
+	reader := bufio.NewReader(file)
+	var partial []byte
+	for {
+		line, err := reader.ReadBytes('\n')
+		if err == nil {
+			if entry, ok := ParseAccessEntry(append(partial, line...)); ok {
+				fn(entry)
+			}
+			partial = nil
+			continue
+		}
+		if err != io.EOF {
+			return fmt.Errorf("读取日志文件失败: %w", err)
+		}
+		// 保留尚未写完的行
+		partial = append(partial, line...)
+
+		if !opts.Follow {
+			if entry, ok := ParseAccessEntry(partial); ok {
+				fn(entry)
+			}
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(tailPollInterval):
+		}
+
+		// 检查日志是否已滚动或被截断
+		rotated, truncated, err := logRotated(file, filename)
+		if err != nil {
+			return err
+		}
+		if truncated {
+			reader.Reset(file)
+			partial = nil
+		}
+		if rotated {
+			newFile, err := os.Open(filename)
+			if err != nil {
+				continue // 新文件可能尚未创建
+			}
+			// 读取旧文件中剩余的内容后切换到新文件
+			rest, _ := io.ReadAll(reader)
+			for _, line := range bytes.Split(append(partial, rest...), []byte("\n")) {
+				if entry, ok := ParseAccessEntry(line); ok {
+					fn(entry)
+				}
+			}
+			file.Close()
+			file = newFile
+			reader.Reset(file)
+			partial = nil
+		}
+	}
+}
+
+// logRotated 检查打开的文件是否已被替换 (rotated) 或截断 (truncated)
+// 文件被截断时会将读取位置重置到文件开头
+func logRotated(file *os.File, filename string) (rotated, truncated bool, err error) {
+	current, err := os.Stat(filename)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return true, false, nil
+		}
+		return false, false, fmt.Errorf("检查日志文件失败: %w", err)
+	}
+	opened, err := file.Stat()
+	if err != nil {
+		return false, false, fmt.Errorf("检查日志文件失败: %w", err)
+	}
+	if !os.SameFile(current, opened) {
+		return true, false, nil
+	}
+
+	offset, err := file.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return false, false, fmt.Errorf("定位日志文件失败: %w", err)
+	}
+	if current.Size() < offset {
+		// 文件被截断，从头开始读取
+		if _, err := file.Seek(0, io.SeekStart); err != nil {
+			return false, false, fmt.Errorf("定位日志文件失败: %w", err)
+		}
+		return false, true, nil
+	}
+	return false, false, nil
+}
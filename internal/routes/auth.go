@@ -0,0 +1,239 @@
+package routes
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/youfun/fastcaddy/pkg/types"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// BcryptCost bcrypt 哈希成本 - 与 caddy hash-password 的默认值一致
+const BcryptCost = 14
+
+// AuthConfig 路由认证配置
+// Users 和 ForwardAuth 二选一
+type AuthConfig struct {
+	Users       map[string]string  // HTTP 基本认证账户: 用户名 -> 密码 (明文会在本地使用 bcrypt 哈希)
+	Realm       string             // HTTP 基本认证域
+	ForwardAuth *ForwardAuthConfig // 转发认证配置 (Authelia, oauth2-proxy 等)
+}
+
+// ForwardAuthConfig 转发认证配置
+// 每个请求先发送到认证服务，2xx 时放行并复制指定的响应头，否则将认证服务的响应返回给客户端
+type ForwardAuthConfig struct {
+	Upstream    string   // 认证服务地址 (如 "authelia:9091"、"https://auth.example.com")
+	URI         string   // 认证请求 URI (如 "/api/verify?rd=https://auth.example.com")
+	CopyHeaders []string // 认证通过后复制到原始请求的响应头 (如 "Remote-User")
+}
+
+// HashPassword 使用 bcrypt 哈希密码
+// 已经是有效 bcrypt 哈希的值原样返回；仅以 "$2" 开头的明文密码仍会被哈希
+func HashPassword(password string) (string, error) {
+	if IsBcryptHash(password) {
+		return password, nil
+	}
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), BcryptCost)
+	if err != nil {
+		return "", fmt.Errorf("哈希密码失败: %w", err)
+	}
+	return string(hash), nil
+}
+
+// IsBcryptHash 判断值是否为格式正确的 bcrypt 哈希 (版本、成本和长度均有效)
+func IsBcryptHash(value string) bool {
+	if !strings.HasPrefix(value, "$2") {
+		return false
+	}
+	_, err := bcrypt.Cost([]byte(value))
+	return err == nil
+}
+
+// BasicAuthHandler 构建 HTTP 基本认证处理器
+func BasicAuthHandler(realm string, users map[string]string) (types.Handler, error) {
+	if len(users) == 0 {
+		return types.Handler{}, fmt.Errorf("至少需要一个账户")
+	}
+
+	// 按用户名排序，保证生成的配置稳定
+	usernames := make([]string, 0, len(users))
+	for username := range users {
+		usernames = append(usernames, username)
+	}
+	sort.Strings(usernames)
+
+	var accounts []types.BasicAuthAccount
+	for _, username := range usernames {
+		if username == "" {
+			return types.Handler{}, fmt.Errorf("用户名不能为空")
+		}
+		hash, err := HashPassword(users[username])
+		if err != nil {
+			return types.Handler{}, err
+		}
+		accounts = append(accounts, types.BasicAuthAccount{
+			Username: username,
+			Password: hash,
+		})
+	}
+
+	return types.Handler{
+		Handler: "authentication",
+		Providers: &types.AuthProviders{
+			HTTPBasic: &types.HTTPBasicAuth{
+				Accounts: accounts,
+				Hash: &types.BasicAuthHash{
+					Algorithm: "bcrypt",
+				},
+				Realm: realm,
+			},
+		},
+	}, nil
+}
+
+// ForwardAuthHandler 构建转发认证处理器 - 对应 Caddyfile 的 forward_auth 指令展开
+func ForwardAuthHandler(cfg ForwardAuthConfig) (types.Handler, error) {
+	if cfg.Upstream == "" {
+		return types.Handler{}, fmt.Errorf("必须指定认证服务地址")
+	}
+	// https:// 认证服务需要启用 TLS 的传输配置
+	upstream, transport, err := ParseUpstream(cfg.Upstream)
+	if err != nil {
+		return types.Handler{}, err
+	}
+	uri := cfg.URI
+	if uri == "" {
+		uri = "/"
+	}
+
+	// 认证通过时将认证服务返回的头部复制到原始请求
+	var copyRoutes []types.Route
+	for _, header := range cfg.CopyHeaders {
+		copyRoutes = append(copyRoutes, types.Route{
+			Handle: []types.Handler{
+				HeadersHandler(&types.HeaderOps{
+					Set: map[string][]string{
+						header: {fmt.Sprintf("{http.reverse_proxy.header.%s}", header)},
+					},
+				}, nil),
+			},
+		})
+	}
+	if len(copyRoutes) == 0 {
+		// handle_response 至少需要一个不写响应的路由，才能继续执行后续处理器
+		copyRoutes = []types.Route{
+			{
+				Handle: []types.Handler{{Handler: "vars"}},
+			},
+		}
+	}
+
+	return types.Handler{
+		Handler:   "reverse_proxy",
+		Upstreams: []types.Upstream{upstream},
+		Transport: transport,
+		Rewrite: &types.ProxyRewrite{
+			Method: "GET",
+			URI:    uri,
+		},
+		UpstreamHeaders: &types.HeadersConfig{
+			Request: &types.HeaderOps{
+				Set: map[string][]string{
+					"X-Forwarded-Method": {"{http.request.method}"},
+					"X-Forwarded-Uri":    {"{http.request.uri}"},
+				},
+			},
+		},
+		HandleResponse: []types.ResponseHandler{
+			{
+				Match: &types.ResponseMatch{
+					StatusCode: []int{2},
+				},
+				Routes: copyRoutes,
+			},
+		},
+	}, nil
+}
+
+// AuthHandler 根据认证配置构建认证处理器
+func AuthHandler(cfg AuthConfig) (types.Handler, error) {
+	if cfg.ForwardAuth != nil {
+		if len(cfg.Users) > 0 {
+			return types.Handler{}, fmt.Errorf("基本认证和转发认证不能同时使用")
+		}
+		return ForwardAuthHandler(*cfg.ForwardAuth)
+	}
+	return BasicAuthHandler(cfg.Realm, cfg.Users)
+}
+
+// IsAuthHandler 判断处理器是否为 Protect 插入的认证处理器
+func IsAuthHandler(handler types.Handler) bool {
+	if handler.Handler == "authentication" {
+		return true
+	}
+	// 转发认证是带有 rewrite 和 handle_response 的 reverse_proxy
+	return handler.Handler == "reverse_proxy" && handler.Rewrite != nil && len(handler.HandleResponse) > 0
+}
+
+// Protect 为指定 ID 的路由添加认证保护
+// 认证处理器插入到路由处理器链的最前面 (IP 访问限制之后)，重复调用会替换已有的认证配置
+func (m *Manager) Protect(id string, cfg AuthConfig) error {
+	auth, err := AuthHandler(cfg)
+	if err != nil {
+		return err
+	}
+
+	handlers, err := m.routeHandlers(id)
+	if err != nil {
+		return err
+	}
+
+	index := authIndex(handlers)
+	if index >= 0 {
+		// 已受保护，替换现有认证处理器
+		return m.client.PutByID(auth, fmt.Sprintf("%s/handle/%d", id, index), "PATCH")
+	}
+	index = 0
+	if len(handlers) > 0 && IsIPRestriction(handlers[0]) {
+		index = 1
+	}
+	return m.client.PutByID(auth, fmt.Sprintf("%s/handle/%d", id, index), "PUT")
+}
+
+// Unprotect 移除指定 ID 路由的认证保护
+func (m *Manager) Unprotect(id string) error {
+	handlers, err := m.routeHandlers(id)
+	if err != nil {
+		return err
+	}
+
+	index := authIndex(handlers)
+	if index < 0 {
+		return fmt.Errorf("路由 %s 未启用认证保护", id)
+	}
+	return m.client.DeleteByID(fmt.Sprintf("%s/handle/%d", id, index))
+}
+
+// authIndex 返回认证处理器在处理器链中的位置，不存在时返回 -1
+// 认证处理器位于链首，或紧跟在 IP 访问限制之后
+func authIndex(handlers []types.Handler) int {
+	for i, handler := range handlers {
+		if IsAuthHandler(handler) {
+			return i
+		}
+		if i > 0 || !IsIPRestriction(handler) {
+			break
+		}
+	}
+	return -1
+}
+
+// routeHandlers 获取指定 ID 路由的处理器列表
+func (m *Manager) routeHandlers(id string) ([]types.Handler, error) {
+	var handlers []types.Handler
+	if err := m.client.GetByIDInto(fmt.Sprintf("%s/handle", id), &handlers); err != nil {
+		return nil, fmt.Errorf("获取路由 %s 失败: %w", id, err)
+	}
+	return handlers, nil
+}
@@ -0,0 +1,373 @@
+package routes
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/youfun/fastcaddy/pkg/types"
+)
+
+// WeightedRoundRobin 加权轮询选择策略名称
+const WeightedRoundRobin = "weighted_round_robin"
+
+// canaryPolicySuffix 金丝雀加权轮询策略 @id 的后缀
+const canaryPolicySuffix = "-canary-policy"
+
+// CanaryStatus 金丝雀发布状态
+type CanaryStatus struct {
+	Stable  []string // 稳定版本上游地址
+	Canary  []string // 金丝雀版本上游地址
+	Percent int      // 金丝雀流量百分比
+}
+
+// CanaryUpstreamID 生成金丝雀上游的 ID
+// 金丝雀上游通过 @id 标记，以便在 promote/abort 时区分稳定版本和金丝雀版本
+func CanaryUpstreamID(routeID string, index int) string {
+	return fmt.Sprintf("%s-canary-%d", routeID, index)
+}
+
+// canaryPolicyID 生成金丝雀加权轮询策略的 ID
+// 金丝雀发布前的选择策略以 base64 编码的 JSON 记录在 ID 中，promote/abort 时恢复；
+// 原来没有选择策略时 ID 不带后缀
+func canaryPolicyID(routeID string, saved interface{}) (string, error) {
+	id := routeID + canaryPolicySuffix
+	if saved == nil {
+		return id, nil
+	}
+	data, err := json.Marshal(saved)
+	if err != nil {
+		return "", fmt.Errorf("序列化选择策略失败: %w", err)
+	}
+	return id + ":" + base64.RawURLEncoding.EncodeToString(data), nil
+}
+
+// savedCanaryPolicy 从金丝雀加权轮询策略的 ID 中解析发布前的选择策略
+// found 为 false 表示策略不是由金丝雀发布设置的 (如旧版本开始的金丝雀发布)
+func savedCanaryPolicy(routeID string, policy map[string]interface{}) (saved interface{}, found bool, err error) {
+	id, _ := policy["@id"].(string)
+	prefix := routeID + canaryPolicySuffix
+	if !strings.HasPrefix(id, prefix) {
+		return nil, false, nil
+	}
+	encoded := strings.TrimPrefix(id, prefix)
+	if encoded == "" {
+		return nil, true, nil
+	}
+	if !strings.HasPrefix(encoded, ":") {
+		return nil, false, nil
+	}
+	data, err := base64.RawURLEncoding.DecodeString(encoded[1:])
+	if err != nil {
+		return nil, false, fmt.Errorf("解析金丝雀发布前的选择策略失败: %w", err)
+	}
+	if err := json.Unmarshal(data, &saved); err != nil {
+		return nil, false, fmt.Errorf("解析金丝雀发布前的选择策略失败: %w", err)
+	}
+	return saved, true, nil
+}
+
+// isCanaryUpstream 判断上游是否为指定路由的金丝雀上游
+func isCanaryUpstream(routeID string, upstream types.Upstream) bool {
+	return strings.HasPrefix(upstream.ID, routeID+"-canary-")
+}
+
+// CanaryWeights 计算稳定版本和金丝雀版本上游的权重
+// 保证所有金丝雀上游的权重之和占总权重的 percent%
+func CanaryWeights(stable, canary, percent int) []int {
+	stableWeight := (100 - percent) * canary
+	canaryWeight := percent * stable
+	if d := gcd(stableWeight, canaryWeight); d > 1 {
+		stableWeight /= d
+		canaryWeight /= d
+	}
+
+	weights := make([]int, 0, stable+canary)
+	for i := 0; i < stable; i++ {
+		weights = append(weights, stableWeight)
+	}
+	for i := 0; i < canary; i++ {
+		weights = append(weights, canaryWeight)
+	}
+	return weights
+}
+
+// gcd 计算最大公约数
+func gcd(a, b int) int {
+	for b != 0 {
+		a, b = b, a%b
+	}
+	return a
+}
+
+// Canary 为指定 ID 的路由配置金丝雀发布
+// 将 percent% 的流量通过加权轮询分配到 newUpstreams，其余流量保留在原有上游。
+// newUpstreams 为空时沿用当前的金丝雀上游，仅调整流量比例
+func (m *Manager) Canary(id string, newUpstreams []string, percent int) error {
+	if percent < 1 || percent > 99 {
+		return fmt.Errorf("金丝雀流量百分比必须在 1-99 之间，全量切换请使用 promote，回滚请使用 abort")
+	}
+
+	handlers, index, err := m.proxyHandler(id)
+	if err != nil {
+		return err
+	}
+	handler := handlers[index]
+	stable, canary, err := splitCanaryUpstreams(id, handler)
+	if err != nil {
+		return err
+	}
+	if len(stable) == 0 {
+		return fmt.Errorf("路由 %s 没有稳定版本上游", id)
+	}
+
+	// 首次开始金丝雀发布时记录原有的选择策略，调整比例时沿用已记录的策略
+	var current map[string]interface{}
+	if lb, ok := handler["load_balancing"].(map[string]interface{}); ok {
+		current, _ = lb["selection_policy"].(map[string]interface{})
+	}
+	policyID, _ := current["@id"].(string)
+	if _, found, err := savedCanaryPolicy(id, current); err != nil {
+		return err
+	} else if !found {
+		var saved interface{}
+		if current != nil {
+			saved = current
+		}
+		if policyID, err = canaryPolicyID(id, saved); err != nil {
+			return err
+		}
+	}
+
+	if len(newUpstreams) > 0 {
+		canary = canary[:0]
+		for i, address := range newUpstreams {
+			upstream, err := canaryUpstream(handler, address)
+			if err != nil {
+				return err
+			}
+			upstream.ID = CanaryUpstreamID(id, i)
+			canary = append(canary, upstream)
+		}
+	}
+	if len(canary) == 0 {
+		return fmt.Errorf("路由 %s 尚未配置金丝雀上游，请指定新的上游地址", id)
+	}
+
+	policy := &types.SelectionPolicy{
+		ID:      policyID,
+		Policy:  WeightedRoundRobin,
+		Weights: CanaryWeights(len(stable), len(canary), percent),
+	}
+	return m.updateProxyUpstreams(id, handlers, index, append(stable, canary...), policy)
+}
+
+// canaryUpstream 解析金丝雀上游地址 (支持 https:// 和 h2c://)
+// 同一个 reverse_proxy 的上游共用传输配置，因此协议必须与稳定版本一致
+func canaryUpstream(handler map[string]interface{}, address string) (types.Upstream, error) {
+	upstream, derived, err := ParseUpstream(address)
+	if err != nil {
+		return types.Upstream{}, err
+	}
+	if !strings.Contains(address, "://") {
+		return upstream, nil
+	}
+
+	var current *types.Transport
+	if raw, ok := handler["transport"]; ok {
+		if err := convertJSON(raw, &current); err != nil {
+			return types.Upstream{}, err
+		}
+	}
+	if want, have := transportScheme(derived), transportScheme(current); want != have {
+		return types.Upstream{}, fmt.Errorf("金丝雀上游 %s 使用 %s，而稳定版本上游使用 %s (同一反向代理的上游共用传输配置)", address, want, have)
+	}
+	return upstream, nil
+}
+
+// transportScheme 返回传输配置对应的上游协议 (http、https 或 h2c)
+func transportScheme(transport *types.Transport) string {
+	if transport == nil {
+		return "http"
+	}
+	if transport.TLS != nil {
+		return "https"
+	}
+	for _, version := range transport.Versions {
+		if version == "h2c" {
+			return "h2c"
+		}
+	}
+	return "http"
+}
+
+// PromoteCanary 将全部流量切换到金丝雀上游，金丝雀上游成为新的稳定版本
+func (m *Manager) PromoteCanary(id string) error {
+	handlers, index, err := m.proxyHandler(id)
+	if err != nil {
+		return err
+	}
+	_, canary, err := splitCanaryUpstreams(id, handlers[index])
+	if err != nil {
+		return err
+	}
+	if len(canary) == 0 {
+		return fmt.Errorf("路由 %s 没有进行中的金丝雀发布", id)
+	}
+
+	// 去除金丝雀标记
+	for i := range canary {
+		canary[i].ID = ""
+	}
+	return m.updateProxyUpstreams(id, handlers, index, canary, nil)
+}
+
+// AbortCanary 放弃金丝雀发布，全部流量回到稳定版本上游
+func (m *Manager) AbortCanary(id string) error {
+	handlers, index, err := m.proxyHandler(id)
+	if err != nil {
+		return err
+	}
+	stable, canary, err := splitCanaryUpstreams(id, handlers[index])
+	if err != nil {
+		return err
+	}
+	if len(canary) == 0 {
+		return fmt.Errorf("路由 %s 没有进行中的金丝雀发布", id)
+	}
+	return m.updateProxyUpstreams(id, handlers, index, stable, nil)
+}
+
+// GetCanaryStatus 获取指定路由的金丝雀发布状态
+func (m *Manager) GetCanaryStatus(id string) (*CanaryStatus, error) {
+	handlers, index, err := m.proxyHandler(id)
+	if err != nil {
+		return nil, err
+	}
+	handler := handlers[index]
+	stable, canary, err := splitCanaryUpstreams(id, handler)
+	if err != nil {
+		return nil, err
+	}
+
+	status := &CanaryStatus{}
+	for _, upstream := range stable {
+		status.Stable = append(status.Stable, upstream.Dial)
+	}
+	for _, upstream := range canary {
+		status.Canary = append(status.Canary, upstream.Dial)
+	}
+
+	// 根据权重计算金丝雀流量百分比
+	var lb types.LoadBalancing
+	if raw, ok := handler["load_balancing"]; ok {
+		if err := convertJSON(raw, &lb); err != nil {
+			return nil, err
+		}
+	}
+	if lb.SelectionPolicy != nil && lb.SelectionPolicy.Policy == WeightedRoundRobin &&
+		len(lb.SelectionPolicy.Weights) == len(stable)+len(canary) {
+		total, canaryTotal := 0, 0
+		for i, weight := range lb.SelectionPolicy.Weights {
+			total += weight
+			if i >= len(stable) {
+				canaryTotal += weight
+			}
+		}
+		if total > 0 {
+			status.Percent = canaryTotal * 100 / total
+		}
+	}
+
+	return status, nil
+}
+
+// proxyHandler 获取路由的处理器列表和最后一个 reverse_proxy 处理器的索引
+// 以原始 map 形式返回，回写时不会丢失未建模的字段
+func (m *Manager) proxyHandler(id string) ([]map[string]interface{}, int, error) {
+	var handlers []map[string]interface{}
+	if err := m.client.GetByIDInto(fmt.Sprintf("%s/handle", id), &handlers); err != nil {
+		return nil, 0, fmt.Errorf("获取路由 %s 失败: %w", id, err)
+	}
+
+	for i := len(handlers) - 1; i >= 0; i-- {
+		if handlers[i]["handler"] == "reverse_proxy" {
+			return handlers, i, nil
+		}
+	}
+	return nil, 0, fmt.Errorf("路由 %s 中没有 reverse_proxy 处理器", id)
+}
+
+// splitCanaryUpstreams 将处理器的上游拆分为稳定版本和金丝雀版本
+func splitCanaryUpstreams(id string, handler map[string]interface{}) ([]types.Upstream, []types.Upstream, error) {
+	var upstreams []types.Upstream
+	if err := convertJSON(handler["upstreams"], &upstreams); err != nil {
+		return nil, nil, err
+	}
+
+	var stable, canary []types.Upstream
+	for _, upstream := range upstreams {
+		if isCanaryUpstream(id, upstream) {
+			canary = append(canary, upstream)
+		} else {
+			stable = append(stable, upstream)
+		}
+	}
+	return stable, canary, nil
+}
+
+// updateProxyUpstreams 在一次请求中更新处理器的上游列表和选择策略，保证流量切换的原子性
+// policy 为 nil 时结束金丝雀发布：恢复策略 ID 中记录的原选择策略；
+// 没有记录时 (旧版本开始的金丝雀发布) 只移除加权轮询策略
+func (m *Manager) updateProxyUpstreams(id string, handlers []map[string]interface{}, index int, upstreams []types.Upstream, policy *types.SelectionPolicy) error {
+	handler := handlers[index]
+	var rawUpstreams interface{}
+	if err := convertJSON(upstreams, &rawUpstreams); err != nil {
+		return err
+	}
+	handler["upstreams"] = rawUpstreams
+
+	lb, _ := handler["load_balancing"].(map[string]interface{})
+	if policy != nil {
+		if lb == nil {
+			lb = make(map[string]interface{})
+		}
+		var rawPolicy interface{}
+		if err := convertJSON(policy, &rawPolicy); err != nil {
+			return err
+		}
+		lb["selection_policy"] = rawPolicy
+		handler["load_balancing"] = lb
+	} else if lb != nil {
+		if sp, ok := lb["selection_policy"].(map[string]interface{}); ok {
+			saved, found, err := savedCanaryPolicy(id, sp)
+			if err != nil {
+				return err
+			}
+			switch {
+			case found && saved != nil:
+				lb["selection_policy"] = saved
+			case found || sp["policy"] == WeightedRoundRobin:
+				delete(lb, "selection_policy")
+			}
+		}
+	}
+	if lb != nil && len(lb) == 0 {
+		delete(handler, "load_balancing")
+	}
+
+	return m.client.PutByID(handlers, fmt.Sprintf("%s/handle", id), "PATCH")
+}
+
+// convertJSON 通过 JSON 序列化在两种表示之间转换 (如 map 与类型化结构)
+func convertJSON(from, to interface{}) error {
+	data, err := json.Marshal(from)
+	if err != nil {
+		return fmt.Errorf("序列化配置失败: %w", err)
+	}
+	if err := json.Unmarshal(data, to); err != nil {
+		return fmt.Errorf("解析配置失败: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,219 @@
+package routes
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/youfun/fastcaddy/pkg/types"
+)
+
+// originPlaceholder 请求的 Origin 头
+const originPlaceholder = "{http.request.header.Origin}"
+
+// 默认允许的跨域方法和请求头
+var (
+	DefaultCORSMethods = []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"}
+	DefaultCORSHeaders = []string{"Content-Type", "Authorization"}
+)
+
+// CORSPolicy 跨域资源共享策略
+type CORSPolicy struct {
+	Origins       []string // 允许的来源 (如 "https://app.example.com"，"*" 表示任意来源，不能与凭据同时使用)
+	Methods       []string // 允许的请求方法 (默认: DefaultCORSMethods)
+	Headers       []string // 允许的请求头 (默认: DefaultCORSHeaders)
+	ExposeHeaders []string // 允许浏览器读取的响应头
+	Credentials   bool     // 是否允许携带凭据 (Cookie、Authorization)
+	MaxAge        int      // 预检结果缓存时间 (秒，0 表示不设置)
+}
+
+// CORSHandlerID 生成路由中 CORS 响应头处理器的 ID
+func CORSHandlerID(id string) string {
+	return "cors-" + id
+}
+
+// CORSPreflightRouteID 生成 CORS 预检路由的 ID
+func CORSPreflightRouteID(id string) string {
+	return "cors-preflight-" + id
+}
+
+// normalize 校验策略并填充默认值
+func (p CORSPolicy) normalize() (CORSPolicy, error) {
+	if len(p.Origins) == 0 {
+		return p, fmt.Errorf("必须指定允许的来源")
+	}
+	for _, origin := range p.Origins {
+		if origin != "*" && !strings.HasPrefix(origin, "http://") && !strings.HasPrefix(origin, "https://") {
+			return p, fmt.Errorf("无效的来源: %s (需要以 http:// 或 https:// 开头)", origin)
+		}
+	}
+	if p.anyOrigin() {
+		if len(p.Origins) > 1 {
+			return p, fmt.Errorf("来源 \"*\" 不能与其他来源同时指定")
+		}
+		// 回显任意来源并允许凭据等同于允许任何网站以用户身份发起请求
+		if p.Credentials {
+			return p, fmt.Errorf("允许凭据时必须明确列出允许的来源，不能使用 \"*\"")
+		}
+	}
+	methods := p.Methods
+	if len(methods) == 0 {
+		methods = DefaultCORSMethods
+	}
+	p.Methods = make([]string, len(methods))
+	for i, method := range methods {
+		p.Methods[i] = strings.ToUpper(method)
+	}
+	if len(p.Headers) == 0 {
+		p.Headers = DefaultCORSHeaders
+	}
+	return p, nil
+}
+
+// anyOrigin 检查是否允许任意来源
+func (p CORSPolicy) anyOrigin() bool {
+	for _, origin := range p.Origins {
+		if origin == "*" {
+			return true
+		}
+	}
+	return false
+}
+
+// originMatch 构建匹配允许来源的请求头条件
+func (p CORSPolicy) originMatch() map[string][]string {
+	if p.anyOrigin() {
+		return map[string][]string{"Origin": {"*"}}
+	}
+	return map[string][]string{"Origin": p.Origins}
+}
+
+// allowOrigin 返回 Access-Control-Allow-Origin 的值
+// 指定来源列表时回显请求的来源 (响应头只在 Origin 匹配列表时添加，见 originMatch)
+func (p CORSPolicy) allowOrigin() string {
+	if p.anyOrigin() {
+		return "*"
+	}
+	return originPlaceholder
+}
+
+// corsHeaders 构建实际请求的 CORS 响应头
+func (p CORSPolicy) corsHeaders() map[string][]string {
+	headers := map[string][]string{
+		"Access-Control-Allow-Origin": {p.allowOrigin()},
+	}
+	if p.Credentials {
+		headers["Access-Control-Allow-Credentials"] = []string{"true"}
+	}
+	if len(p.ExposeHeaders) > 0 {
+		headers["Access-Control-Expose-Headers"] = []string{strings.Join(p.ExposeHeaders, ", ")}
+	}
+	return headers
+}
+
+// preflightHeaders 构建预检请求的 CORS 响应头
+func (p CORSPolicy) preflightHeaders() map[string][]string {
+	headers := p.corsHeaders()
+	delete(headers, "Access-Control-Expose-Headers")
+	headers["Access-Control-Allow-Methods"] = []string{strings.Join(p.Methods, ", ")}
+	headers["Access-Control-Allow-Headers"] = []string{strings.Join(p.Headers, ", ")}
+	if p.MaxAge > 0 {
+		headers["Access-Control-Max-Age"] = []string{strconv.Itoa(p.MaxAge)}
+	}
+	return headers
+}
+
+// CORSHandler 构建为跨域请求添加响应头并应答预检请求的处理器
+// 使用子路由仅在 Origin 被允许时添加响应头，OPTIONS 预检请求直接返回 204，其余请求继续执行原有处理器。
+// 处理器位于路由的处理器链中 (IP 访问限制和认证之后)，预检请求同样需要通过这些检查
+func CORSHandler(id string, policy CORSPolicy) (types.Handler, error) {
+	policy, err := policy.normalize()
+	if err != nil {
+		return types.Handler{}, err
+	}
+
+	return types.Handler{
+		Handler: "subroute",
+		ID:      CORSHandlerID(id),
+		Routes: []types.Route{
+			{
+				Match: []types.RouteMatch{
+					{Header: policy.originMatch()},
+				},
+				Handle: []types.Handler{
+					HeadersHandler(nil, &types.RespHeaderOps{
+						HeaderOps: types.HeaderOps{
+							Set: policy.corsHeaders(),
+							Add: map[string][]string{"Vary": {"Origin"}},
+						},
+					}),
+				},
+			},
+			corsPreflightRoute(id, policy),
+		},
+	}, nil
+}
+
+// corsPreflightRoute 构建 CORS 子路由中应答 OPTIONS 预检请求的路由
+// 允许的来源和 Vary 响应头由子路由的第一个路由添加，这里只补充预检相关的响应头
+func corsPreflightRoute(id string, policy CORSPolicy) types.Route {
+	headers := policy.preflightHeaders()
+	for key := range policy.corsHeaders() {
+		delete(headers, key)
+	}
+
+	return types.Route{
+		ID: CORSPreflightRouteID(id),
+		Match: []types.RouteMatch{
+			{
+				Method: []string{"OPTIONS"},
+				Header: policy.originMatch(),
+			},
+		},
+		Handle: []types.Handler{
+			StaticResponseHandler(204, "", headers),
+		},
+		Terminal: true,
+	}
+}
+
+// SetCORS 为指定 ID 的路由设置 CORS 策略
+// 在路由的最终处理器之前插入 CORS 处理器 (同时应答预检请求)；重复调用会更新现有策略
+func (m *Manager) SetCORS(id string, policy CORSPolicy) error {
+	handler, err := CORSHandler(id, policy)
+	if err != nil {
+		return err
+	}
+
+	handlers, err := m.routeHandlers(id)
+	if err != nil {
+		return err
+	}
+	for i, h := range handlers {
+		if h.ID == handler.ID {
+			if err := m.client.PutByID(handler, fmt.Sprintf("%s/handle/%d", id, i), "PATCH"); err != nil {
+				return fmt.Errorf("更新 CORS 处理器失败: %w", err)
+			}
+			return nil
+		}
+	}
+
+	// 插入到最终处理器 (如 reverse_proxy) 之前
+	index := len(handlers) - 1
+	if index < 0 {
+		index = 0
+	}
+	if err := m.client.PutByID(handler, fmt.Sprintf("%s/handle/%d", id, index), "PUT"); err != nil {
+		return fmt.Errorf("添加 CORS 处理器失败: %w", err)
+	}
+	return nil
+}
+
+// RemoveCORS 移除指定 ID 路由的 CORS 策略
+func (m *Manager) RemoveCORS(id string) error {
+	handlerID := CORSHandlerID(id)
+	if !m.client.HasID(handlerID) {
+		return fmt.Errorf("路由 %s 未设置 CORS 策略", id)
+	}
+	return m.client.DeleteByID(handlerID)
+}
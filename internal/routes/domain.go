@@ -0,0 +1,280 @@
+package routes
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/youfun/fastcaddy/pkg/types"
+)
+
+// Domain 通配符域名 - 管理 *.<域名> 下的子域名路由
+// 所有子域名路由保存在通配符路由的 subroute 处理器中，兜底路由始终位于最后
+type Domain struct {
+	Name    string // 域名 (如 "example.com")
+	manager *Manager
+}
+
+// Subdomain 子域名反向代理
+type Subdomain struct {
+	Name      string   // 子域名 (如 "api")
+	Host      string   // 完整主机名 (如 "api.example.com")
+	Upstreams []string // 上游地址列表
+}
+
+// FallbackOptions 未知子域名的兜底处理选项
+type FallbackOptions struct {
+	Upstream   string // 兜底上游地址 (为空时返回静态响应)
+	StatusCode int    // 静态响应状态码 (默认: 404)
+	Body       string // 静态响应内容
+}
+
+// WildcardRouteID 生成通配符路由的 ID
+func WildcardRouteID(domain string) string {
+	return fmt.Sprintf("wildcard-%s", domain)
+}
+
+// Domain 返回指定域名的通配符域名管理对象
+func (m *Manager) Domain(name string) *Domain {
+	return &Domain{Name: name, manager: m}
+}
+
+// ID 返回通配符路由的 ID
+func (d *Domain) ID() string {
+	return WildcardRouteID(d.Name)
+}
+
+// SubdomainID 返回子域名路由的 ID
+func (d *Domain) SubdomainID(subdomain string) string {
+	return fmt.Sprintf("%s.%s", subdomain, d.Name)
+}
+
+// FallbackID 返回兜底路由的 ID
+func (d *Domain) FallbackID() string {
+	return fmt.Sprintf("wildcard-fallback-%s", d.Name)
+}
+
+// routesPath 返回子域名路由列表相对于通配符路由 ID 的路径
+// 访问限制、认证等处理器会插入到子路由处理器之前，因此按类型查找最后一个 subroute 处理器
+func (d *Domain) routesPath() (string, error) {
+	var handlers []struct {
+		Handler string `json:"handler"`
+	}
+	if err := d.manager.client.GetByIDInto(d.ID()+"/handle", &handlers); err != nil {
+		return "", fmt.Errorf("获取域名 %s 的通配符路由失败: %w", d.Name, err)
+	}
+	for i := len(handlers) - 1; i >= 0; i-- {
+		if handlers[i].Handler == "subroute" {
+			return fmt.Sprintf("%s/handle/%d/routes", d.ID(), i), nil
+		}
+	}
+	return "", fmt.Errorf("域名 %s 的通配符路由中没有子路由处理器", d.Name)
+}
+
+// Ensure 确保通配符路由存在，已存在时不做修改
+func (d *Domain) Ensure() error {
+	if d.manager.client.HasID(d.ID()) {
+		return nil
+	}
+
+	// 创建通配符路由配置
+	route := types.Route{
+		ID: d.ID(),
+		Match: []types.RouteMatch{
+			{
+				Host: []string{fmt.Sprintf("*.%s", d.Name)}, // 通配符匹配
+			},
+		},
+		Handle: []types.Handler{
+			{
+				Handler: "subroute", // 使用子路由处理器
+				Routes:  []types.Route{},
+			},
+		},
+		Terminal: true,
+	}
+	return d.manager.AddRoute(route)
+}
+
+// routes 获取通配符路由下的所有路由及路由列表的路径
+func (d *Domain) routes() (string, []types.Route, error) {
+	path, err := d.routesPath()
+	if err != nil {
+		return "", nil, err
+	}
+	var routes []types.Route
+	if err := d.manager.client.GetByIDInto(path, &routes); err != nil {
+		return "", nil, fmt.Errorf("获取域名 %s 的子域名失败: %w", d.Name, err)
+	}
+	return path, routes, nil
+}
+
+// indexOf 返回指定 ID 的路由在列表中的位置，不存在时返回 -1
+func indexOf(routes []types.Route, id string) int {
+	for i, route := range routes {
+		if route.ID == id {
+			return i
+		}
+	}
+	return -1
+}
+
+// Subdomains 列出所有子域名 (不包括兜底路由)
+func (d *Domain) Subdomains() ([]Subdomain, error) {
+	_, routes, err := d.routes()
+	if err != nil {
+		return nil, err
+	}
+
+	suffix := "." + d.Name
+	var subdomains []Subdomain
+	for _, route := range routes {
+		if route.ID == d.FallbackID() || !strings.HasSuffix(route.ID, suffix) {
+			continue
+		}
+
+		sub := Subdomain{
+			Name: strings.TrimSuffix(route.ID, suffix),
+			Host: route.ID,
+		}
+		for _, handler := range route.Handle {
+			if handler.Handler != "reverse_proxy" {
+				continue
+			}
+			for _, upstream := range handler.Upstreams {
+				sub.Upstreams = append(sub.Upstreams, upstream.Dial)
+			}
+		}
+		subdomains = append(subdomains, sub)
+	}
+	return subdomains, nil
+}
+
+// validateSubdomain 验证子域名 (通配符只匹配一级子域名)
+func validateSubdomain(subdomain string) error {
+	if subdomain == "" || strings.ContainsAny(subdomain, ".*/ ") {
+		return fmt.Errorf("无效的子域名: %s", subdomain)
+	}
+	return nil
+}
+
+// SetSubdomain 添加或更新子域名反向代理
+// 已存在的子域名在原位置替换，新子域名插入到兜底路由之前；重复调用不会产生重复路由
+func (d *Domain) SetSubdomain(subdomain string, upstreams []string, opts ProxyOptions) error {
+	if err := validateSubdomain(subdomain); err != nil {
+		return err
+	}
+	if len(upstreams) == 0 {
+		return fmt.Errorf("必须指定至少一个上游地址")
+	}
+
+	var ups []types.Upstream
+	for _, dial := range upstreams {
+		ups = append(ups, types.Upstream{Dial: dial})
+	}
+
+	// 构建处理器链
+	id := d.SubdomainID(subdomain)
+	handlers, err := ProxyHandlers(id, ups, opts)
+	if err != nil {
+		return err
+	}
+	if err := d.manager.checkRateLimit(id, opts, handlers); err != nil {
+		return err
+	}
+
+	if err := d.Ensure(); err != nil {
+		return err
+	}
+	path, routes, err := d.routes()
+	if err != nil {
+		return err
+	}
+
+	route := types.Route{
+		ID: id,
+		Match: []types.RouteMatch{
+			{
+				Host: []string{id},
+			},
+		},
+		Handle: handlers,
+	}
+
+	if i := indexOf(routes, route.ID); i >= 0 {
+		return d.manager.client.PutByID(route, fmt.Sprintf("%s/%d", path, i), "PATCH")
+	}
+	if i := indexOf(routes, d.FallbackID()); i >= 0 {
+		return d.manager.client.PutByID(route, fmt.Sprintf("%s/%d", path, i), "PUT")
+	}
+	return d.appendRoute(path, routes, route)
+}
+
+// appendRoute 将路由追加到子域名路由列表
+// 空的子路由序列化时不包含 routes 键 (读取为 nil)，此时需要以数组形式创建
+func (d *Domain) appendRoute(path string, routes []types.Route, route types.Route) error {
+	if routes == nil {
+		return d.manager.client.PutByID([]types.Route{route}, path, "POST")
+	}
+	return d.manager.client.PutByID(route, path, "POST")
+}
+
+// RemoveSubdomain 删除子域名
+func (d *Domain) RemoveSubdomain(subdomain string) error {
+	path, routes, err := d.routes()
+	if err != nil {
+		return err
+	}
+
+	i := indexOf(routes, d.SubdomainID(subdomain))
+	if i < 0 {
+		return fmt.Errorf("子域名 %s 不存在", d.SubdomainID(subdomain))
+	}
+	return d.manager.client.DeleteByID(fmt.Sprintf("%s/%d", path, i))
+}
+
+// SetFallback 设置未知子域名的兜底处理
+// 兜底路由不带匹配条件，位于所有子域名之后
+func (d *Domain) SetFallback(opts FallbackOptions) error {
+	var handler types.Handler
+	if opts.Upstream != "" {
+		upstream, transport, err := ParseUpstream(opts.Upstream)
+		if err != nil {
+			return err
+		}
+		handler = ReverseProxyHandler([]types.Upstream{upstream}, ProxyOptions{Transport: transport})
+	} else {
+		statusCode := opts.StatusCode
+		if statusCode == 0 {
+			statusCode = 404
+		}
+		handler = StaticResponseHandler(statusCode, opts.Body, nil)
+	}
+
+	if err := d.Ensure(); err != nil {
+		return err
+	}
+	path, routes, err := d.routes()
+	if err != nil {
+		return err
+	}
+
+	route := types.Route{
+		ID:       d.FallbackID(),
+		Handle:   []types.Handler{handler},
+		Terminal: true,
+	}
+	if i := indexOf(routes, route.ID); i >= 0 {
+		if err := d.manager.client.DeleteByID(fmt.Sprintf("%s/%d", path, i)); err != nil {
+			return fmt.Errorf("删除现有兜底路由失败: %w", err)
+		}
+	}
+	return d.appendRoute(path, routes, route)
+}
+
+// RemoveFallback 删除兜底路由
+func (d *Domain) RemoveFallback() error {
+	if !d.manager.client.HasID(d.FallbackID()) {
+		return fmt.Errorf("域名 %s 未设置兜底路由", d.Name)
+	}
+	return d.manager.client.DeleteByID(d.FallbackID())
+}
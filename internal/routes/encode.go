@@ -0,0 +1,71 @@
+package routes
+
+import (
+	"fmt"
+
+	"github.com/youfun/fastcaddy/pkg/types"
+)
+
+// CompressionOptions 响应压缩选项
+type CompressionOptions struct {
+	Encodings     []string // 编码格式，按优先顺序排列 (默认: zstd, gzip)
+	MinimumLength int      // 启用压缩的最小响应长度 (0 表示使用 Caddy 默认值 512)
+	ContentTypes  []string // 仅压缩这些 Content-Type (支持 text/* 形式，为空时使用 Caddy 默认列表)
+}
+
+// CompressionHandler 构建 encode 处理器
+func CompressionHandler(opts CompressionOptions) types.Handler {
+	encodings := opts.Encodings
+	if len(encodings) == 0 {
+		encodings = []string{"zstd", "gzip"}
+	}
+
+	handler := types.Handler{
+		Handler:       "encode",
+		Encodings:     make(map[string]types.Encoding),
+		Prefer:        encodings,
+		MinimumLength: opts.MinimumLength,
+	}
+	for _, encoding := range encodings {
+		handler.Encodings[encoding] = types.Encoding{}
+	}
+
+	if len(opts.ContentTypes) > 0 {
+		handler.Match = &types.ResponseMatch{
+			Headers: map[string][]string{
+				"Content-Type": opts.ContentTypes,
+			},
+		}
+	}
+
+	return handler
+}
+
+// EnableCompression 为指定 ID 的路由启用响应压缩
+// encode 处理器插入到终端处理器 (最后一个处理器) 之前，已启用时替换为默认配置
+func (m *Manager) EnableCompression(id string) error {
+	return m.EnableCompressionWithOptions(id, CompressionOptions{})
+}
+
+// EnableCompressionWithOptions 使用指定选项为路由启用响应压缩
+func (m *Manager) EnableCompressionWithOptions(id string, opts CompressionOptions) error {
+	handlers, err := m.routeHandlers(id)
+	if err != nil {
+		return err
+	}
+	if len(handlers) == 0 {
+		return fmt.Errorf("路由 %s 没有处理器", id)
+	}
+
+	encode := CompressionHandler(opts)
+
+	// 已存在 encode 处理器时原地替换
+	for i, handler := range handlers {
+		if handler.Handler == "encode" {
+			return m.client.PutByID(encode, fmt.Sprintf("%s/handle/%d", id, i), "PATCH")
+		}
+	}
+
+	// 插入到终端处理器之前
+	return m.client.PutByID(encode, fmt.Sprintf("%s/handle/%d", id, len(handlers)-1), "PUT")
+}
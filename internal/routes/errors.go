@@ -0,0 +1,155 @@
+package routes
+
+import (
+	"fmt"
+	"path"
+	"sort"
+
+	"github.com/youfun/fastcaddy/pkg/types"
+)
+
+// ErrorStatusPlaceholder 错误状态码占位符 - 保持原始错误的状态码
+const ErrorStatusPlaceholder = "{http.error.status_code}"
+
+// ErrorPageOptions 错误页面选项
+type ErrorPageOptions struct {
+	Host string // 仅对该主机生效 (为空时对服务器上的所有主机生效)
+	Root string // 错误页面所在目录，设置后 pages 的值为该目录下的文件名，否则为内联 HTML
+}
+
+// ErrorRoutesPath 返回指定服务器的错误处理路由路径
+func ErrorRoutesPath(serverName string) string {
+	return fmt.Sprintf("%s/%s/errors/routes", ServersPath, serverName)
+}
+
+// ErrorPagesRouteID 生成错误页面路由的 ID
+func ErrorPagesRouteID(host string) string {
+	if host == "" {
+		return "errors-all"
+	}
+	return RouteID("errors", host, "")
+}
+
+// ErrorPagesRoute 根据状态码到页面的映射构建错误处理路由
+// 状态码 0 表示其他所有错误的默认页面
+func ErrorPagesRoute(pages map[int]string, opts ErrorPageOptions) (types.Route, error) {
+	if len(pages) == 0 {
+		return types.Route{}, fmt.Errorf("至少需要一个错误页面")
+	}
+
+	// 具体状态码按升序排列，默认页面 (0) 放在最后
+	codes := make([]int, 0, len(pages))
+	for code := range pages {
+		if code != 0 && (code < 400 || code > 599) {
+			return types.Route{}, fmt.Errorf("无效的错误状态码: %d", code)
+		}
+		codes = append(codes, code)
+	}
+	sort.Slice(codes, func(i, j int) bool {
+		if codes[i] == 0 || codes[j] == 0 {
+			return codes[j] == 0 && codes[i] != 0
+		}
+		return codes[i] < codes[j]
+	})
+
+	var subroutes []types.Route
+	for _, code := range codes {
+		subroute := types.Route{
+			Handle: errorPageHandlers(pages[code], opts.Root),
+		}
+		if code != 0 {
+			subroute.Match = []types.RouteMatch{
+				{
+					Expression: fmt.Sprintf("%s in [%d]", ErrorStatusPlaceholder, code),
+				},
+			}
+		}
+		subroutes = append(subroutes, subroute)
+	}
+
+	route := types.Route{
+		ID: ErrorPagesRouteID(opts.Host),
+		Handle: []types.Handler{
+			{
+				Handler: "subroute",
+				Routes:  subroutes,
+			},
+		},
+		Terminal: true,
+	}
+	if opts.Host != "" {
+		route.Match = []types.RouteMatch{
+			{
+				Host: []string{opts.Host},
+			},
+		}
+	}
+
+	return route, nil
+}
+
+// errorPageHandlers 构建单个错误页面的处理器链
+func errorPageHandlers(page, root string) []types.Handler {
+	// 内联 HTML
+	if root == "" {
+		return []types.Handler{
+			{
+				Handler:    "static_response",
+				StatusCode: ErrorStatusPlaceholder,
+				Body:       page,
+				Headers: map[string][]string{
+					"Content-Type": {"text/html; charset=utf-8"},
+				},
+			},
+		}
+	}
+
+	// 从目录中读取错误页面文件
+	return []types.Handler{
+		{
+			Handler: "rewrite",
+			URI:     path.Join("/", page),
+		},
+		{
+			Handler:    "file_server",
+			Root:       root,
+			StatusCode: ErrorStatusPlaceholder,
+		},
+	}
+}
+
+// SetErrorPages 设置服务器的自定义错误页面
+// pages 为状态码到页面的映射 (0 表示默认页面)，重复调用会替换同一主机的错误页面配置
+func (m *Manager) SetErrorPages(serverName string, pages map[int]string, opts ErrorPageOptions) error {
+	route, err := ErrorPagesRoute(pages, opts)
+	if err != nil {
+		return err
+	}
+
+	// 确保错误处理路由列表存在
+	routesPath := ErrorRoutesPath(serverName)
+	if !m.client.HasValue(path.Dir(routesPath)) {
+		errorsConfig := types.HTTPErrorConfig{Routes: []types.Route{}}
+		if err := m.client.PutConfig(errorsConfig, path.Dir(routesPath), "POST"); err != nil {
+			return fmt.Errorf("初始化错误处理配置失败: %w", err)
+		}
+	}
+
+	// 删除同一主机已有的错误页面路由
+	if m.client.HasID(route.ID) {
+		if err := m.client.DeleteByID(route.ID); err != nil {
+			return fmt.Errorf("删除现有错误页面失败: %w", err)
+		}
+	}
+
+	// 主机专属的错误页面需要优先于全局错误页面
+	if opts.Host != "" {
+		return m.client.PutConfig(route, routesPath+"/0", "PUT")
+	}
+	return m.client.PutConfig(route, routesPath, "POST")
+}
+
+// RemoveErrorPages 移除指定主机的自定义错误页面 (host 为空时移除全局错误页面)
+func (m *Manager) RemoveErrorPages(host string) error {
+	return m.client.DeleteByID(ErrorPagesRouteID(host))
+}
@@ -0,0 +1,105 @@
+package routes
+
+import (
+	"fmt"
+
+	"github.com/youfun/fastcaddy/pkg/types"
+)
+
+// FileServerOptions 静态文件服务选项
+type FileServerOptions struct {
+	Browse        bool     // 是否启用目录浏览
+	IndexNames    []string // 索引文件名列表 (为空时使用 Caddy 默认值 index.html, index.txt)
+	Precompressed []string // 预压缩文件格式，按优先顺序排列 (如 "br", "zstd", "gzip")
+	Hide          []string // 隐藏的文件或目录模式
+	HideDotfiles  bool     // 是否隐藏以 '.' 开头的文件和目录
+	SPA           bool     // 是否启用单页应用回退 (找不到文件时返回 /index.html)
+	SPAIndex      string   // SPA 回退文件 (默认: /index.html)
+}
+
+// FileServerHandler 根据选项构建 file_server 处理器
+func FileServerHandler(root string, opts FileServerOptions) types.Handler {
+	handler := types.Handler{
+		Handler:    "file_server",
+		Root:       root,
+		IndexNames: opts.IndexNames,
+		Hide:       opts.Hide,
+	}
+
+	if opts.HideDotfiles {
+		handler.Hide = append(handler.Hide, ".*")
+	}
+
+	if opts.Browse {
+		handler.Browse = &types.FileBrowse{}
+	}
+
+	// 启用预压缩文件，保持用户给定的优先顺序
+	if len(opts.Precompressed) > 0 {
+		handler.Precompressed = make(map[string]struct{})
+		for _, format := range opts.Precompressed {
+			handler.Precompressed[format] = struct{}{}
+		}
+		handler.PrecompressedOrder = opts.Precompressed
+	}
+
+	return handler
+}
+
+// AddFileServer 添加静态文件服务路由
+// 为指定主机提供 root 目录下的静态文件，SPA 模式下找不到的路径会回退到索引文件
+func (m *Manager) AddFileServer(host, root string, opts FileServerOptions) error {
+	if root == "" {
+		return fmt.Errorf("必须指定站点根目录")
+	}
+
+	fileServer := FileServerHandler(root, opts)
+
+	route := types.Route{
+		ID: host,
+		Match: []types.RouteMatch{
+			{
+				Host: []string{host},
+			},
+		},
+		Handle:   []types.Handler{fileServer},
+		Terminal: true,
+	}
+
+	// SPA 模式：等价于 Caddyfile 中的 try_files {path} /index.html
+	if opts.SPA {
+		index := opts.SPAIndex
+		if index == "" {
+			index = "/index.html"
+		}
+
+		route.Handle = []types.Handler{
+			{
+				Handler: "subroute",
+				Routes: []types.Route{
+					{
+						Match: []types.RouteMatch{
+							{
+								File: &types.FileMatch{
+									Root:     root,
+									TryFiles: []string{"{http.request.uri.path}", index},
+								},
+							},
+						},
+						Handle: []types.Handler{
+							{
+								Handler: "rewrite",
+								URI:     "{http.matchers.file.relative}",
+							},
+						},
+					},
+					{
+						Handle: []types.Handler{fileServer},
+					},
+				},
+			},
+		}
+	}
+
+	return m.ReplaceRoute(route)
+}
@@ -0,0 +1,49 @@
+package routes
+
+import "github.com/youfun/fastcaddy/pkg/types"
+
+// UpstreamHostPlaceholder 上游地址占位符 - 用于将 Host 头改写为上游地址
+const UpstreamHostPlaceholder = "{http.reverse_proxy.upstream.hostport}"
+
+// HeadersHandler 构建 headers 处理器
+func HeadersHandler(request *types.HeaderOps, response *types.RespHeaderOps) types.Handler {
+	return types.Handler{
+		Handler:  "headers",
+		Request:  request,
+		Response: response,
+	}
+}
+
+// SecurityHeaders 返回常用安全响应头预设
+// 包括 HSTS、禁止 MIME 嗅探、防止点击劫持、Referrer 策略，并移除 Server 头
+func SecurityHeaders() *types.RespHeaderOps {
+	return &types.RespHeaderOps{
+		HeaderOps: types.HeaderOps{
+			Set: map[string][]string{
+				"Strict-Transport-Security": {"max-age=31536000; includeSubDomains"},
+				"X-Content-Type-Options":    {"nosniff"},
+				"X-Frame-Options":           {"SAMEORIGIN"},
+				"Referrer-Policy":           {"strict-origin-when-cross-origin"},
+				"Content-Security-Policy":   {"frame-ancestors 'self'"},
+			},
+			Delete: []string{"Server"},
+		},
+		// Server 头由 Caddy 在写响应时添加，必须延迟执行才能删除
+		Deferred: true,
+	}
+}
+
+// SecurityHeadersHandler 返回应用安全响应头预设的 headers 处理器
+func SecurityHeadersHandler() types.Handler {
+	return HeadersHandler(nil, SecurityHeaders())
+}
+
+// RewriteUpstreamHost 返回将 Host 头改写为上游地址的请求头操作
+// 适用于按 Host 区分站点的上游 (如对象存储、托管平台)
+func RewriteUpstreamHost() *types.HeaderOps {
+	return &types.HeaderOps{
+		Set: map[string][]string{
+			"Host": {UpstreamHostPlaceholder},
+		},
+	}
+}
@@ -0,0 +1,369 @@
+package routes
+
+import (
+	"encoding/json"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/youfun/fastcaddy/pkg/types"
+)
+
+// tlsAppPath TLS 应用的配置路径
+const tlsAppPath = "/apps/tls"
+
+// ImportOptions 导入配置的选项
+type ImportOptions struct {
+	ServerName string // 合并到的服务器名称 (仅在导入的配置只有一个服务器时生效，默认使用原服务器名)
+	Replace    bool   // 是否整体替换正在运行的配置 (默认合并)
+}
+
+// ImportResult 导入结果
+type ImportResult struct {
+	Added    []string // 新增的路由 ID
+	Replaced []string // 替换的已有路由 ID
+	Merged   []string // 合并的配置路径 (如 TLS 自动化策略，仅合并模式)
+	Skipped  []string // 因已存在而跳过的配置路径 (仅合并模式)
+}
+
+// AssignRouteIDs 为没有 @id 的路由分配 fastcaddy 风格的 ID
+// ID 根据第一个匹配条件的主机名和路径生成 (与 RouteID 相同)，重复时追加序号
+func AssignRouteIDs(routes []interface{}, taken map[string]bool) {
+	for i, r := range routes {
+		route, ok := r.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if id, ok := route["@id"].(string); ok && id != "" {
+			taken[id] = true
+			continue
+		}
+
+		host, path := firstMatch(route)
+		base := RouteID("", host, path)
+		if host == "" {
+			base = RouteID("import", fmt.Sprintf("%d", i), path)
+		}
+		id := base
+		for n := 2; taken[id]; n++ {
+			id = fmt.Sprintf("%s-%d", base, n)
+		}
+		route["@id"] = id
+		taken[id] = true
+	}
+}
+
+// firstMatch 返回路由第一个匹配集中的第一个主机名和路径
+func firstMatch(route map[string]interface{}) (string, string) {
+	matches, _ := route["match"].([]interface{})
+	if len(matches) == 0 {
+		return "", ""
+	}
+	set, _ := matches[0].(map[string]interface{})
+	first := func(key string) string {
+		values, _ := set[key].([]interface{})
+		if len(values) == 0 {
+			return ""
+		}
+		s, _ := values[0].(string)
+		return s
+	}
+	return first("host"), first("path")
+}
+
+// sortedKeys 返回按名称排序的键列表
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// configServers 返回配置中的应用和 HTTP 服务器
+func configServers(cfg map[string]interface{}) (map[string]interface{}, map[string]interface{}) {
+	apps, _ := cfg["apps"].(map[string]interface{})
+	httpApp, _ := apps["http"].(map[string]interface{})
+	servers, _ := httpApp["servers"].(map[string]interface{})
+	return apps, servers
+}
+
+// AssignConfigIDs 为配置中所有 HTTP 服务器的路由分配 @id
+// taken 为已被占用的 ID (如正在运行的配置中的 @id)，分配的 ID 不会与其重复；可以为 nil
+func AssignConfigIDs(cfg map[string]interface{}, taken map[string]bool) {
+	_, servers := configServers(cfg)
+	if taken == nil {
+		taken = make(map[string]bool)
+	}
+	for _, name := range sortedKeys(servers) {
+		server, _ := servers[name].(map[string]interface{})
+		routes, _ := server["routes"].([]interface{})
+		AssignRouteIDs(routes, taken)
+	}
+}
+
+// AssignImportIDs 为待导入配置中的路由分配 @id
+// 合并模式下分配的 ID 不会与正在运行的配置中的 @id 重复，避免覆盖已有的路由
+func (m *Manager) AssignImportIDs(cfg map[string]interface{}, opts ImportOptions) error {
+	taken := make(map[string]bool)
+	if !opts.Replace {
+		var live interface{}
+		if err := m.client.GetConfigInto("/", &live); err != nil {
+			return fmt.Errorf("获取配置失败: %w", err)
+		}
+		ids, err := types.CollectIDs(live)
+		if err != nil {
+			return err
+		}
+		for _, id := range ids {
+			taken[id] = true
+		}
+	}
+	AssignConfigIDs(cfg, taken)
+	return nil
+}
+
+// ImportConfig 导入 JSON 配置 (如 Caddyfile 经 /adapt 转换的结果)
+// 为没有 @id 的 HTTP 路由分配 @id。默认合并到正在运行的配置：导入配置中显式指定了 @id
+// 且位于同一服务器的路由原地替换，其余路由追加；TLS 自动化策略按 subjects 替换或添加，
+// 其他应用和顶层配置仅在运行配置中不存在时添加；Replace 模式下通过 /load 整体替换配置。
+// 合并会发送多个请求，需要原子性时应在事务中调用 (FastCaddy.ImportCaddyfile 即如此)
+func (m *Manager) ImportConfig(data []byte, opts ImportOptions) (*ImportResult, error) {
+	var cfg map[string]interface{}
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return nil, fmt.Errorf("解析导入配置失败: %w", err)
+	}
+
+	if err := m.AssignImportIDs(cfg, opts); err != nil {
+		return nil, err
+	}
+	if err := types.Validate(cfg); err != nil {
+		return nil, fmt.Errorf("导入配置校验失败: %w", err)
+	}
+	apps, servers := configServers(cfg)
+	result := &ImportResult{}
+
+	if opts.Replace {
+		for _, name := range sortedKeys(servers) {
+			server, _ := servers[name].(map[string]interface{})
+			routes, _ := server["routes"].([]interface{})
+			result.Added = append(result.Added, routeIDs(routes)...)
+		}
+		if err := m.client.Load(cfg); err != nil {
+			return nil, fmt.Errorf("加载配置失败: %w", err)
+		}
+		return result, nil
+	}
+
+	for _, name := range sortedKeys(servers) {
+		target := name
+		if opts.ServerName != "" && len(servers) == 1 {
+			target = opts.ServerName
+		}
+		server, _ := servers[name].(map[string]interface{})
+		if err := m.mergeServer(target, server, result); err != nil {
+			return nil, err
+		}
+	}
+
+	// TLS 应用已存在时逐项合并，其余应用和顶层配置 (如 logging) 已存在的保持不变
+	others := make(map[string]interface{})
+	for name, app := range apps {
+		if name == "tls" && m.client.HasValue(tlsAppPath) {
+			if err := m.mergeTLS(app, result); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		if name != "http" {
+			others["/apps/"+name] = app
+		}
+	}
+	for key, value := range cfg {
+		if key != "apps" {
+			others["/"+key] = value
+		}
+	}
+	for _, cfgPath := range sortedKeys(others) {
+		if err := m.addIfMissing(cfgPath, others[cfgPath], result); err != nil {
+			return nil, err
+		}
+	}
+
+	return result, nil
+}
+
+// mergeServer 将导入的服务器路由合并到正在运行的服务器
+// 服务器不存在时整体添加，否则该服务器中相同 ID 的路由原地替换，其余路由追加到末尾
+func (m *Manager) mergeServer(serverName string, server map[string]interface{}, result *ImportResult) error {
+	serverPath := fmt.Sprintf("%s/%s", ServersPath, serverName)
+	routes, _ := server["routes"].([]interface{})
+
+	if !m.client.HasValue(serverPath) {
+		if err := m.configManager.EnsurePath(ServersPath); err != nil {
+			return err
+		}
+		if err := m.client.PutConfig(server, serverPath, "POST"); err != nil {
+			return fmt.Errorf("添加服务器 %s 失败: %w", serverName, err)
+		}
+		result.Added = append(result.Added, routeIDs(routes)...)
+		return nil
+	}
+
+	// 只替换该服务器中的路由，其他位置的同名 @id 由写入时的校验拒绝
+	var existing []interface{}
+	if err := m.client.GetConfigInto(serverPath+"/routes", &existing); err != nil {
+		return fmt.Errorf("获取服务器 %s 的路由失败: %w", serverName, err)
+	}
+	current := make(map[string]bool)
+	for _, id := range routeIDs(existing) {
+		current[id] = true
+	}
+
+	// 服务器没有 routes 键时，第一个路由需要以数组形式创建
+	hasRoutes := existing != nil
+	for _, r := range routes {
+		route, ok := r.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		id, _ := route["@id"].(string)
+		if current[id] {
+			if err := m.client.PutByID(route, id, "PATCH"); err != nil {
+				return fmt.Errorf("替换路由 %s 失败: %w", id, err)
+			}
+			result.Replaced = append(result.Replaced, id)
+			continue
+		}
+		var value interface{} = route
+		if !hasRoutes {
+			value = []interface{}{route}
+		}
+		if err := m.client.PutConfig(value, serverPath+"/routes", "POST"); err != nil {
+			return fmt.Errorf("添加路由 %s 失败: %w", id, err)
+		}
+		hasRoutes = true
+		result.Added = append(result.Added, id)
+	}
+	return nil
+}
+
+// mergeTLS 将导入的 TLS 应用合并到已存在的 TLS 应用
+// 自动化策略按 subjects 合并 (相同 subjects 的策略原地替换，其余插入到兜底策略之前)，
+// 其他配置项仅在不存在时添加
+func (m *Manager) mergeTLS(app interface{}, result *ImportResult) error {
+	tlsApp, _ := app.(map[string]interface{})
+	for _, key := range sortedKeys(tlsApp) {
+		keyPath := tlsAppPath + "/" + key
+		if key != "automation" || !m.client.HasValue(keyPath) {
+			if err := m.addIfMissing(keyPath, tlsApp[key], result); err != nil {
+				return err
+			}
+			continue
+		}
+
+		automation, _ := tlsApp[key].(map[string]interface{})
+		for _, name := range sortedKeys(automation) {
+			if name == "policies" {
+				if err := m.mergePolicies(automation[name], result); err != nil {
+					return err
+				}
+				continue
+			}
+			if err := m.addIfMissing(keyPath+"/"+name, automation[name], result); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// mergePolicies 按 subjects 合并 TLS 自动化策略，一次写回整个策略列表
+func (m *Manager) mergePolicies(value interface{}, result *ImportResult) error {
+	policiesPath := tlsAppPath + "/automation/policies"
+	imported, _ := value.([]interface{})
+	var policies []interface{}
+	method := "POST"
+	if m.client.HasValue(policiesPath) {
+		method = "PATCH"
+		if err := m.client.GetConfigInto(policiesPath, &policies); err != nil {
+			return fmt.Errorf("获取 TLS 自动化策略失败: %w", err)
+		}
+	}
+
+	for _, policy := range imported {
+		key := policySubjects(policy)
+		index := -1
+		for i, existing := range policies {
+			if policySubjects(existing) == key {
+				index = i
+				break
+			}
+		}
+		switch {
+		case index >= 0:
+			policies[index] = policy
+		case key == "":
+			policies = append(policies, policy)
+		default:
+			// 没有 subjects 的兜底策略匹配所有证书，新策略需要排在它之前
+			index = len(policies)
+			for i, existing := range policies {
+				if policySubjects(existing) == "" {
+					index = i
+					break
+				}
+			}
+			policies = append(policies[:index], append([]interface{}{policy}, policies[index:]...)...)
+		}
+	}
+
+	if err := m.client.PutConfig(policies, policiesPath, method); err != nil {
+		return fmt.Errorf("合并 TLS 自动化策略失败: %w", err)
+	}
+	result.Merged = append(result.Merged, policiesPath)
+	return nil
+}
+
+// policySubjects 返回策略排序后的 subjects，用于比较两个策略是否针对相同的域名
+func policySubjects(policy interface{}) string {
+	p, _ := policy.(map[string]interface{})
+	values, _ := p["subjects"].([]interface{})
+	subjects := make([]string, 0, len(values))
+	for _, v := range values {
+		if s, ok := v.(string); ok {
+			subjects = append(subjects, s)
+		}
+	}
+	sort.Strings(subjects)
+	return strings.Join(subjects, ",")
+}
+
+// addIfMissing 配置路径不存在时添加，已存在时记录为跳过
+func (m *Manager) addIfMissing(cfgPath string, value interface{}, result *ImportResult) error {
+	if m.client.HasValue(cfgPath) {
+		result.Skipped = append(result.Skipped, cfgPath)
+		return nil
+	}
+	if err := m.configManager.EnsurePath(path.Dir(cfgPath)); err != nil {
+		return err
+	}
+	if err := m.client.PutConfig(value, cfgPath, "POST"); err != nil {
+		return fmt.Errorf("添加 %s 失败: %w", cfgPath, err)
+	}
+	return nil
+}
+
+// routeIDs 返回路由列表中的所有 @id
+func routeIDs(routes []interface{}) []string {
+	var ids []string
+	for _, r := range routes {
+		route, _ := r.(map[string]interface{})
+		if id, ok := route["@id"].(string); ok {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
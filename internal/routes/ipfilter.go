@@ -0,0 +1,328 @@
+package routes
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/youfun/fastcaddy/pkg/types"
+)
+
+// 常量定义 - IP 访问限制相关 ID 前缀
+const (
+	ipRestrictionPrefix = "ip-restrict-"
+	ipListPrefix        = "iplist-"
+	ipListRefPrefix     = "@" // allow/deny 列表中引用命名 IP 列表的前缀 (如 "@vpn")
+)
+
+// ipListNamePattern 命名 IP 列表名称格式
+var ipListNamePattern = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+// IPRestrictionOptions IP 访问限制选项
+type IPRestrictionOptions struct {
+	Allow  []string // 允许访问的地址 (IP、CIDR 或 "@名称" 引用命名 IP 列表)
+	Deny   []string // 拒绝访问的地址 (同上)
+	SaveAs string   // 将 Allow 中直接指定的地址保存为命名 IP 列表，供其他路由通过 "@名称" 引用
+}
+
+// IPRestrictionID 生成路由中 IP 访问限制处理器的 ID
+func IPRestrictionID(id string) string {
+	return ipRestrictionPrefix + id
+}
+
+// ipListRefID 生成保存命名 IP 列表的匹配集 ID，格式为 iplist-<名称>:<allow|deny>:<路由 ID>
+// 命名 IP 列表不单独保存，而是保存在引用它的访问限制的匹配集中，所有引用的地址保持一致
+func ipListRefID(name, kind, id string) string {
+	return fmt.Sprintf("%s%s:%s:%s", ipListPrefix, name, kind, id)
+}
+
+// ipListRefs 返回引用指定命名 IP 列表的所有匹配集 ID
+func (m *Manager) ipListRefs(name string) ([]string, error) {
+	return m.findIDs(ipListPrefix + name + ":")
+}
+
+// IsIPRestriction 判断处理器是否为 RestrictIPs 插入的 IP 访问限制处理器
+func IsIPRestriction(handler types.Handler) bool {
+	return handler.Handler == "subroute" && strings.HasPrefix(handler.ID, ipRestrictionPrefix)
+}
+
+// validateIPListName 验证命名 IP 列表名称
+func validateIPListName(name string) error {
+	if !ipListNamePattern.MatchString(name) {
+		return fmt.Errorf("无效的 IP 列表名称: %s (只能包含字母、数字、'-' 和 '_')", name)
+	}
+	return nil
+}
+
+// SetIPList 更新命名 IP 列表，所有引用该列表的访问限制同步更新
+// 命名 IP 列表通过 RestrictIPsWithOptions 的 SaveAs 创建，没有被引用的列表不存在
+func (m *Manager) SetIPList(name string, ranges []string) error {
+	if err := validateIPListName(name); err != nil {
+		return err
+	}
+	if len(ranges) == 0 {
+		return fmt.Errorf("IP 列表不能为空")
+	}
+	if err := validateRanges(ranges); err != nil {
+		return err
+	}
+
+	refs, err := m.ipListRefs(name)
+	if err != nil {
+		return err
+	}
+	if len(refs) == 0 {
+		return fmt.Errorf("IP 列表 %s 不存在 (需要先在访问限制中通过 SaveAs 创建)", name)
+	}
+	for _, ref := range refs {
+		if err := m.client.PutByID(ranges, ref+"/client_ip/ranges", "PATCH"); err != nil {
+			return fmt.Errorf("更新 %s 失败: %w", ref, err)
+		}
+	}
+	return nil
+}
+
+// GetIPList 获取命名 IP 列表
+func (m *Manager) GetIPList(name string) ([]string, error) {
+	refs, err := m.ipListRefs(name)
+	if err != nil {
+		return nil, err
+	}
+	if len(refs) == 0 {
+		return nil, fmt.Errorf("IP 列表 %s 不存在", name)
+	}
+	var ranges []string
+	if err := m.client.GetByIDInto(refs[0]+"/client_ip/ranges", &ranges); err != nil {
+		return nil, fmt.Errorf("获取 IP 列表 %s 失败: %w", name, err)
+	}
+	return ranges, nil
+}
+
+// IPLists 返回所有命名 IP 列表的名称
+func (m *Manager) IPLists() ([]string, error) {
+	refs, err := m.findIDs(ipListPrefix)
+	if err != nil {
+		return nil, err
+	}
+	var names []string
+	seen := make(map[string]bool)
+	for _, ref := range refs {
+		name, _, _ := strings.Cut(strings.TrimPrefix(ref, ipListPrefix), ":")
+		if !seen[name] {
+			seen[name] = true
+			names = append(names, name)
+		}
+	}
+	return names, nil
+}
+
+// DeleteIPList 删除命名 IP 列表
+// 引用处保留当前的地址，但不再随列表更新
+func (m *Manager) DeleteIPList(name string) error {
+	refs, err := m.ipListRefs(name)
+	if err != nil {
+		return err
+	}
+	if len(refs) == 0 {
+		return fmt.Errorf("IP 列表 %s 不存在", name)
+	}
+	for _, ref := range refs {
+		var set types.RouteMatch
+		if err := m.client.GetByIDInto(ref, &set); err != nil {
+			return fmt.Errorf("获取 %s 失败: %w", ref, err)
+		}
+		set.ID = ""
+		if err := m.client.PutByID(set, ref, "PATCH"); err != nil {
+			return fmt.Errorf("更新 %s 失败: %w", ref, err)
+		}
+	}
+	return nil
+}
+
+// ipMatchSets 将 IP/CIDR 和命名列表引用转换为匹配集
+// 直接指定的地址合并为一个匹配集 (saveAs 不为空时以该名称保存为命名 IP 列表)，
+// 每个引用的命名列表各自对应一个带 ID 的匹配集，便于列表更新时同步
+func (m *Manager) ipMatchSets(id, kind string, entries []string, saveAs string) ([]types.RouteMatch, error) {
+	var ranges []string
+	var sets []types.RouteMatch
+	for _, entry := range entries {
+		if !strings.HasPrefix(entry, ipListRefPrefix) {
+			ranges = append(ranges, entry)
+			continue
+		}
+
+		name := strings.TrimPrefix(entry, ipListRefPrefix)
+		if err := validateIPListName(name); err != nil {
+			return nil, err
+		}
+		listRanges, err := m.GetIPList(name)
+		if err != nil {
+			return nil, err
+		}
+		sets = append(sets, types.RouteMatch{
+			ID:       ipListRefID(name, kind, id),
+			ClientIP: &types.IPMatch{Ranges: listRanges},
+		})
+	}
+
+	if err := validateRanges(ranges); err != nil {
+		return nil, err
+	}
+	if len(ranges) > 0 {
+		set := types.RouteMatch{ClientIP: &types.IPMatch{Ranges: ranges}}
+		if saveAs != "" {
+			set.ID = ipListRefID(saveAs, kind, id)
+		}
+		sets = append([]types.RouteMatch{set}, sets...)
+	}
+	return sets, nil
+}
+
+// IPRestrictionHandler 构建 IP 访问限制处理器
+// allow 和 deny 中的元素可以是 IP、CIDR 或 "@名称" 形式的命名 IP 列表引用。
+// 命中 deny 或不在 allow 中的请求返回 403，其余请求继续执行原有处理器。
+// 使用 client_ip 匹配，未配置受信任代理时与 remote_ip 相同
+func (m *Manager) IPRestrictionHandler(id string, allow, deny []string) (types.Handler, error) {
+	return m.ipRestrictionHandler(id, IPRestrictionOptions{Allow: allow, Deny: deny})
+}
+
+// ipRestrictionHandler 根据选项构建 IP 访问限制处理器
+func (m *Manager) ipRestrictionHandler(id string, opts IPRestrictionOptions) (types.Handler, error) {
+	if len(opts.Allow) == 0 && len(opts.Deny) == 0 {
+		return types.Handler{}, fmt.Errorf("必须指定允许或拒绝的地址")
+	}
+	if opts.SaveAs != "" {
+		if err := m.checkSaveAs(id, opts); err != nil {
+			return types.Handler{}, err
+		}
+	}
+
+	denySets, err := m.ipMatchSets(id, "deny", opts.Deny, "")
+	if err != nil {
+		return types.Handler{}, err
+	}
+	allowSets, err := m.ipMatchSets(id, "allow", opts.Allow, opts.SaveAs)
+	if err != nil {
+		return types.Handler{}, err
+	}
+
+	forbidden := []types.Handler{
+		StaticResponseHandler(403, "Forbidden", nil),
+	}
+	var routes []types.Route
+	if len(denySets) > 0 {
+		routes = append(routes, types.Route{
+			Match:    denySets,
+			Handle:   forbidden,
+			Terminal: true,
+		})
+	}
+	if len(allowSets) > 0 {
+		routes = append(routes, types.Route{
+			Match:    []types.RouteMatch{{Not: allowSets}},
+			Handle:   forbidden,
+			Terminal: true,
+		})
+	}
+
+	return types.Handler{
+		Handler: "subroute",
+		ID:      IPRestrictionID(id),
+		Routes:  routes,
+	}, nil
+}
+
+// checkSaveAs 检查 SaveAs 的名称可用，且 Allow 中有直接指定的地址
+// 同名列表只能由该路由自己保存 (重复设置时更新)，其他路由需要通过 "@名称" 引用
+func (m *Manager) checkSaveAs(id string, opts IPRestrictionOptions) error {
+	if err := validateIPListName(opts.SaveAs); err != nil {
+		return err
+	}
+	plain := false
+	for _, entry := range opts.Allow {
+		if entry == ipListRefPrefix+opts.SaveAs {
+			return fmt.Errorf("不能在保存 IP 列表 %s 的同时引用它", opts.SaveAs)
+		}
+		if !strings.HasPrefix(entry, ipListRefPrefix) {
+			plain = true
+		}
+	}
+	if !plain {
+		return fmt.Errorf("保存为 IP 列表 %s 需要在允许列表中直接指定地址", opts.SaveAs)
+	}
+
+	refs, err := m.ipListRefs(opts.SaveAs)
+	if err != nil {
+		return err
+	}
+	own := ipListRefID(opts.SaveAs, "allow", id)
+	for _, ref := range refs {
+		if ref != own {
+			return fmt.Errorf("IP 列表 %s 已存在，请使用 @%s 引用或通过 SetIPList 更新", opts.SaveAs, opts.SaveAs)
+		}
+	}
+	return nil
+}
+
+// RestrictIPs 限制指定 ID 路由的访问来源
+// IP 访问限制处理器插入到处理器链的最前面，重复调用会替换已有的限制
+func (m *Manager) RestrictIPs(id string, allow, deny []string) error {
+	return m.RestrictIPsWithOptions(id, IPRestrictionOptions{Allow: allow, Deny: deny})
+}
+
+// RestrictIPsWithOptions 与 RestrictIPs 相同，但支持将允许的地址保存为命名 IP 列表
+// 命名 IP 列表保存在引用它的访问限制中，最后一个引用被移除时列表随之删除
+func (m *Manager) RestrictIPsWithOptions(id string, opts IPRestrictionOptions) error {
+	handler, err := m.ipRestrictionHandler(id, opts)
+	if err != nil {
+		return err
+	}
+
+	handlers, err := m.routeHandlers(id)
+	if err != nil {
+		return err
+	}
+	if len(handlers) > 0 && IsIPRestriction(handlers[0]) {
+		return m.client.PutByID(handler, fmt.Sprintf("%s/handle/0", id), "PATCH")
+	}
+	return m.client.PutByID(handler, fmt.Sprintf("%s/handle/0", id), "PUT")
+}
+
+// Unrestrict 移除指定 ID 路由的 IP 访问限制
+func (m *Manager) Unrestrict(id string) error {
+	if !m.client.HasID(IPRestrictionID(id)) {
+		return fmt.Errorf("路由 %s 未设置 IP 访问限制", id)
+	}
+	return m.client.DeleteByID(IPRestrictionID(id))
+}
+
+// findIDs 查找配置中所有以指定前缀开头的 @id
+func (m *Manager) findIDs(prefix string) ([]string, error) {
+	config, err := m.client.GetConfig("/")
+	if err != nil {
+		return nil, fmt.Errorf("获取配置失败: %w", err)
+	}
+
+	var ids []string
+	var walk func(v interface{})
+	walk = func(v interface{}) {
+		switch val := v.(type) {
+		case map[string]interface{}:
+			if id, ok := val["@id"].(string); ok && strings.HasPrefix(id, prefix) {
+				ids = append(ids, id)
+			}
+			for _, child := range val {
+				walk(child)
+			}
+		case []interface{}:
+			for _, child := range val {
+				walk(child)
+			}
+		}
+	}
+	walk(config)
+
+	sort.Strings(ids)
+	return ids, nil
+}
@@ -0,0 +1,208 @@
+package routes
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/youfun/fastcaddy/pkg/types"
+)
+
+// ClientIPKey 按客户端 IP 限流的键
+const ClientIPKey = "{http.request.remote.host}"
+
+// rateLimitProbe 检测限流模块时通过 /adapt 转换的 Caddyfile (只转换，不加载)
+// 缺少 rate_limit 模块时 Caddyfile 适配器不认识该指令
+const rateLimitProbe = `:0 {
+	route {
+		rate_limit {
+			zone fastcaddy_probe {
+				key static
+				events 1
+				window 1s
+			}
+		}
+	}
+}`
+
+// rateLimitAvailable Caddy 管理地址 -> 是否包含 rate_limit 模块 (检测结果在进程内缓存)
+var rateLimitAvailable sync.Map
+
+// ErrRateLimitUnavailable 当前 Caddy 未包含 rate_limit 模块
+var ErrRateLimitUnavailable = errors.New("当前 Caddy 未包含 rate_limit 模块 (需要 github.com/mholt/caddy-ratelimit)")
+
+// RateLimitOptions 限流选项
+type RateLimitOptions struct {
+	Zone   string        // 限流区域名称 (默认: "rl-<路由 ID>"，同名区域在所有路由间共享计数)
+	Key    string        // 限流键 (默认: ClientIPKey)
+	Events int           // 窗口内允许的最大请求数
+	Window time.Duration // 窗口时长
+}
+
+// ParseRate 解析限流速率，格式为 "<次数>r/<单位>"
+// 单位可以是 s、m、h 或时长 (如 "100r/m", "10r/s", "500r/15m")
+func ParseRate(rate string) (RateLimitOptions, error) {
+	parts := strings.SplitN(strings.TrimSpace(rate), "/", 2)
+	if len(parts) != 2 || !strings.HasSuffix(parts[0], "r") {
+		return RateLimitOptions{}, fmt.Errorf("无效的限流速率: %s (示例: 100r/m)", rate)
+	}
+
+	events, err := strconv.Atoi(strings.TrimSuffix(parts[0], "r"))
+	if err != nil || events <= 0 {
+		return RateLimitOptions{}, fmt.Errorf("无效的限流次数: %s", rate)
+	}
+
+	unit := parts[1]
+	switch unit {
+	case "s", "m", "h":
+		unit = "1" + unit
+	}
+	window, err := time.ParseDuration(unit)
+	if err != nil || window <= 0 {
+		return RateLimitOptions{}, fmt.Errorf("无效的限流窗口: %s", rate)
+	}
+
+	return RateLimitOptions{Events: events, Window: window}, nil
+}
+
+// HeaderKey 生成按请求头限流的键 (如按 API Key 限流)
+func HeaderKey(header string) string {
+	return fmt.Sprintf("{http.request.header.%s}", header)
+}
+
+// RateLimitZoneName 生成路由默认的限流区域名称，使每个路由独立计数
+func RateLimitZoneName(routeID string) string {
+	return "rl-" + routeID
+}
+
+// RateLimitHandler 构建 rate_limit 处理器，未指定区域名称时使用路由默认的区域
+func RateLimitHandler(routeID string, opts RateLimitOptions) (types.Handler, error) {
+	if opts.Events <= 0 || opts.Window <= 0 {
+		return types.Handler{}, fmt.Errorf("限流次数和窗口必须大于 0")
+	}
+
+	zone := opts.Zone
+	if zone == "" {
+		zone = RateLimitZoneName(routeID)
+	}
+	key := opts.Key
+	if key == "" {
+		key = ClientIPKey
+	}
+
+	return types.Handler{
+		Handler: "rate_limit",
+		RateLimits: map[string]*types.RateLimitZone{
+			zone: {
+				Key:       key,
+				Window:    types.Duration(opts.Window.String()),
+				MaxEvents: opts.Events,
+			},
+		},
+	}, nil
+}
+
+// RequestBodyHandler 构建限制请求体大小的 request_body 处理器
+// 超出大小的请求体在读取时会返回 413 错误
+func RequestBodyHandler(maxSize int64) types.Handler {
+	return types.Handler{
+		Handler: "request_body",
+		MaxSize: maxSize,
+	}
+}
+
+// RateLimitAvailable 检测当前 Caddy 是否包含 rate_limit 模块
+// 通过 /adapt 转换一段使用 rate_limit 指令的 Caddyfile 判断，不修改正在运行的配置
+func (m *Manager) RateLimitAvailable() (bool, error) {
+	if available, ok := rateLimitAvailable.Load(m.client.BaseURL); ok {
+		return available.(bool), nil
+	}
+	_, err := m.client.Adapt([]byte(rateLimitProbe), "caddyfile")
+	if err != nil && !strings.Contains(err.Error(), "unrecognized directive: rate_limit") {
+		return false, fmt.Errorf("检测 rate_limit 模块失败: %w", err)
+	}
+	available := err == nil
+	rateLimitAvailable.Store(m.client.BaseURL, available)
+	return available, nil
+}
+
+// checkRateLimit 启用限流时确认 rate_limit 模块可用，避免替换路由时因加载失败丢失原有路由；
+// 同时检查限流区域是否与其他路由中同名但配置不同的区域冲突
+func (m *Manager) checkRateLimit(routeID string, opts ProxyOptions, handlers []types.Handler) error {
+	if opts.RateLimit == nil {
+		return nil
+	}
+	if err := m.checkRateLimitZones(routeID, handlers); err != nil {
+		return err
+	}
+	available, err := m.RateLimitAvailable()
+	if err != nil {
+		return err
+	}
+	if !available {
+		return ErrRateLimitUnavailable
+	}
+	return nil
+}
+
+// checkRateLimitZones 检查处理器中的限流区域与现有配置是否冲突
+// 同名区域在 Caddy 中共享计数，因此只允许配置完全相同的同名区域；被替换的路由 routeID 不参与检查
+func (m *Manager) checkRateLimitZones(routeID string, handlers []types.Handler) error {
+	zones := make(map[string]*types.RateLimitZone)
+	for _, handler := range handlers {
+		for name, zone := range handler.RateLimits {
+			zones[name] = zone
+		}
+	}
+	if len(zones) == 0 || !m.client.HasValue(ServersPath) {
+		return nil
+	}
+
+	var servers map[string]types.HTTPServer
+	if err := m.client.GetConfigInto(ServersPath, &servers); err != nil {
+		return fmt.Errorf("获取服务器配置失败: %w", err)
+	}
+	for _, server := range servers {
+		if err := rateLimitConflict(routeID, server.Routes, zones); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// rateLimitConflict 递归检查路由 (包括子路由) 中的同名限流区域
+func rateLimitConflict(routeID string, routes []types.Route, zones map[string]*types.RateLimitZone) error {
+	for _, route := range routes {
+		if route.ID != "" && route.ID == routeID {
+			continue
+		}
+		for _, handler := range route.Handle {
+			for name, existing := range handler.RateLimits {
+				zone, ok := zones[name]
+				if ok && existing != nil && !sameRateLimitZone(zone, existing) {
+					return fmt.Errorf("限流区域 %s 已被路由 %s 以不同的配置使用 (同名区域共享计数，请使用其他区域名称)", name, route.ID)
+				}
+			}
+			if err := rateLimitConflict(routeID, handler.Routes, zones); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// sameRateLimitZone 比较两个限流区域的配置是否相同 (窗口按时长比较)
+func sameRateLimitZone(a, b *types.RateLimitZone) bool {
+	if a.Key != b.Key || a.MaxEvents != b.MaxEvents {
+		return false
+	}
+	wa, errA := time.ParseDuration(string(a.Window))
+	wb, errB := time.ParseDuration(string(b.Window))
+	if errA != nil || errB != nil {
+		return a.Window == b.Window
+	}
+	return wa == wb
+}
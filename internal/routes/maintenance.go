@@ -0,0 +1,91 @@
+package routes
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/youfun/fastcaddy/pkg/types"
+)
+
+// DefaultMaintenanceBody 默认维护页面
+const DefaultMaintenanceBody = `<!DOCTYPE html>
+<html>
+<head><meta charset="utf-8"><title>Service Unavailable</title></head>
+<body><h1>Service Unavailable</h1><p>We are performing scheduled maintenance. Please try again shortly.</p></body>
+</html>
+`
+
+// MaintenanceOptions 维护模式选项
+type MaintenanceOptions struct {
+	Body       string   // 维护页面内容 (默认: DefaultMaintenanceBody)
+	RetryAfter int      // Retry-After 响应头的秒数 (0 表示不设置)
+	Allow      []string // 允许绕过维护页面的 IP 或 CIDR 列表
+}
+
+// MaintenanceRouteID 生成维护模式路由的 ID
+func MaintenanceRouteID(host string) string {
+	return RouteID("maintenance", host, "")
+}
+
+// MaintenanceRoute 构建维护模式路由
+func MaintenanceRoute(host string, opts MaintenanceOptions) (types.Route, error) {
+	if err := validateRanges(opts.Allow); err != nil {
+		return types.Route{}, err
+	}
+
+	body := opts.Body
+	if body == "" {
+		body = DefaultMaintenanceBody
+	}
+
+	headers := map[string][]string{
+		"Content-Type": {"text/html; charset=utf-8"},
+	}
+	if opts.RetryAfter > 0 {
+		headers["Retry-After"] = []string{strconv.Itoa(opts.RetryAfter)}
+	}
+
+	match := types.RouteMatch{
+		Host: []string{host},
+	}
+	// 允许列表中的地址不匹配维护路由，继续访问原有服务
+	if len(opts.Allow) > 0 {
+		match.Not = []types.RouteMatch{
+			{
+				RemoteIP: &types.IPMatch{Ranges: opts.Allow},
+			},
+		}
+	}
+
+	return types.Route{
+		ID:       MaintenanceRouteID(host),
+		Match:    []types.RouteMatch{match},
+		Handle:   []types.Handler{StaticResponseHandler(http.StatusServiceUnavailable, body, headers)},
+		Terminal: true,
+	}, nil
+}
+
+// EnableMaintenance 为指定主机开启维护模式
+// 在路由列表最前面插入返回 503 的路由，原有路由保持不变
+func (m *Manager) EnableMaintenance(host string, opts MaintenanceOptions) error {
+	route, err := MaintenanceRoute(host, opts)
+	if err != nil {
+		return err
+	}
+	return m.replaceRouteAt(route, 0)
+}
+
+// DisableMaintenance 关闭指定主机的维护模式
+func (m *Manager) DisableMaintenance(host string) error {
+	id := MaintenanceRouteID(host)
+	if !m.client.HasID(id) {
+		return fmt.Errorf("主机 %s 未处于维护模式", host)
+	}
+	return m.client.DeleteByID(id)
+}
+
+// InMaintenance 检查指定主机是否处于维护模式
+func (m *Manager) InMaintenance(host string) bool {
+	return m.client.HasID(MaintenanceRouteID(host))
+}
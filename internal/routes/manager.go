@@ -1,11 +1,14 @@
 package routes
 
 import (
+	"encoding/json"
 	"fmt"
 	"strconv"
+	"strings"
 
 	"github.com/youfun/fastcaddy/internal/api"
 	"github.com/youfun/fastcaddy/internal/config"
+	"github.com/youfun/fastcaddy/internal/utils"
 	"github.com/youfun/fastcaddy/pkg/types"
 )
 
@@ -29,29 +32,106 @@ func NewManager() *Manager {
 	}
 }
 
+// NewManagerWithClient 使用指定的 API 客户端创建路由管理器
+func NewManagerWithClient(client *api.Client) *Manager {
+	return &Manager{
+		client:        client,
+		configManager: config.NewManagerWithClient(client),
+	}
+}
+
+// DefaultServer 返回默认的 HTTP 服务器配置
+// 监听 80/443 端口，仅启用 HTTP/1.1 和 HTTP/2（规避 caddy+chrome 的 HTTP/3 问题）
+func DefaultServer() types.HTTPServer {
+	return types.HTTPServer{
+		Listen:    []string{":80", ":443"}, // 监听 HTTP 和 HTTPS 端口
+		Routes:    []types.Route{},         // 空路由列表
+		Protocols: []string{"h1", "h2"},    // 支持 HTTP/1.1 和 HTTP/2
+	}
+}
+
 // InitRoutes 初始化 HTTP 路由配置 - 对应 Python 的 init_routes(srv_name, skip) 函数
 // 创建基础的 HTTP 服务器和路由配置
 func (m *Manager) InitRoutes(serverName string, skip int) error {
+	return m.InitServer(serverName, skip, DefaultServer())
+}
+
+// InitServer 使用指定的服务器配置初始化 HTTP 服务器
+// 与 InitRoutes 相同，但允许自定义协议、监听器包装器和超时等选项
+func (m *Manager) InitServer(serverName string, skip int, server types.HTTPServer) error {
 	// 如果服务器路径已存在，直接返回
 	if m.client.HasPath(ServersPath) {
 		return nil
 	}
 
+	// 在创建中间路径之前校验服务器配置
+	if err := types.Validate(server); err != nil {
+		return fmt.Errorf("服务器配置校验失败: %w", err)
+	}
+
 	// 初始化服务器路径
 	if err := m.configManager.InitPath(ServersPath, skip); err != nil {
 		return err
 	}
 
-	// 创建基础 HTTP 服务器配置
-	serverConfig := types.HTTPServer{
-		Listen:    []string{":80", ":443"},           // 监听 HTTP 和 HTTPS 端口
-		Routes:    []types.Route{},                   // 空路由列表
-		Protocols: []string{"h1", "h2"},              // 支持 HTTP/1.1 和 HTTP/2
+	// 确保路由列表不为 nil，避免序列化为 null
+	if server.Routes == nil {
+		server.Routes = []types.Route{}
 	}
 
 	// 设置服务器配置
 	serverPath := fmt.Sprintf("%s/%s", ServersPath, serverName)
-	return m.client.PutConfig(serverConfig, serverPath, "POST")
+	return m.client.PutConfig(server, serverPath, "POST")
+}
+
+// ConfigureServer 更新已存在服务器的协议选项
+// 只覆盖 options 中非空的字段（协议、监听器包装器、受信任代理、超时），
+// 监听地址和路由保持不变
+func (m *Manager) ConfigureServer(serverName string, options types.HTTPServer) error {
+	serverPath := fmt.Sprintf("%s/%s", ServersPath, serverName)
+
+	// 获取当前服务器配置
+	current, err := m.client.GetConfig(serverPath)
+	if err != nil {
+		return fmt.Errorf("获取服务器 %s 配置失败: %w", serverName, err)
+	}
+
+	// 将选项转换为 map，omitempty 会自动去除未设置的字段
+	data, err := json.Marshal(options)
+	if err != nil {
+		return fmt.Errorf("序列化服务器选项失败: %w", err)
+	}
+	var overrides map[string]interface{}
+	if err := json.Unmarshal(data, &overrides); err != nil {
+		return fmt.Errorf("解析服务器选项失败: %w", err)
+	}
+
+	// 路由不通过此方法修改，监听地址仅在显式指定时覆盖
+	delete(overrides, "routes")
+	if len(options.Listen) == 0 {
+		delete(overrides, "listen")
+	}
+	for key, value := range overrides {
+		current[key] = value
+	}
+
+	// 整体替换服务器配置
+	return m.client.PutConfig(current, serverPath, "PATCH")
+}
+
+// ProxyProtocolWrappers 生成启用 PROXY protocol 的监听器包装器列表
+// proxy_protocol 必须位于 tls 之前，因此显式追加 tls 包装器
+func ProxyProtocolWrappers(timeout types.Duration, allow []string) []types.ListenerWrapper {
+	return []types.ListenerWrapper{
+		{
+			Wrapper: "proxy_protocol",
+			Timeout: timeout,
+			Allow:   allow,
+		},
+		{
+			Wrapper: "tls",
+		},
+	}
 }
 
 // AddRoute 添加路由规则 - 对应 Python 的 add_route(route) 函数
@@ -60,6 +140,63 @@ func (m *Manager) AddRoute(route types.Route) error {
 	return m.client.PutConfig(route, RoutesPath, "POST")
 }
 
+// RouteID 根据主机名和路径生成路由 ID
+// Caddy 通过 /id/<ID>/... 访问配置，ID 中不能包含 '/'，因此路径按 JSON Pointer 的方式转义
+// ('~' 转义为 "~0"，'/' 转义为 "~1"，如 "/old/*" 转义为 "~1old~1*")；不以 '/' 开头的路径 (如 "*.php")
+// 前加 "~2"。主机名中不会出现 '~'，因此不同的主机名和路径 (包括通配符) 不会生成相同的 ID
+func RouteID(prefix, host, path string) string {
+	id := host
+	if prefix != "" {
+		id = prefix + "-" + id
+	}
+	if path == "" {
+		return id
+	}
+	escaped := strings.ReplaceAll(strings.ReplaceAll(path, "~", "~0"), "/", "~1")
+	if !strings.HasPrefix(path, "/") {
+		escaped = "~2" + escaped
+	}
+	return id + escaped
+}
+
+// validateRanges 验证 IP/CIDR 范围列表
+func validateRanges(ranges []string) error {
+	for _, r := range ranges {
+		if !utils.ValidateCIDR(r) {
+			return fmt.Errorf("无效的 IP 或 CIDR: %s", r)
+		}
+	}
+	return nil
+}
+
+// InsertRoute 在指定位置插入路由规则
+// 位置越靠前优先级越高，index 为 0 时插入到最前面
+func (m *Manager) InsertRoute(route types.Route, index int) error {
+	path := fmt.Sprintf("%s/%d", RoutesPath, index)
+	return m.client.PutConfig(route, path, "PUT")
+}
+
+// ReplaceRoute 添加或替换路由规则
+// 如果已存在相同 ID 的路由，先删除再添加，保证重复调用的幂等性
+func (m *Manager) ReplaceRoute(route types.Route) error {
+	if route.ID != "" && m.client.HasID(route.ID) {
+		if err := m.client.DeleteByID(route.ID); err != nil {
+			return fmt.Errorf("删除现有路由失败: %w", err)
+		}
+	}
+	return m.AddRoute(route)
+}
+
+// replaceRouteAt 在指定位置插入路由，如果已存在相同 ID 的路由则先删除
+func (m *Manager) replaceRouteAt(route types.Route, index int) error {
+	if route.ID != "" && m.client.HasID(route.ID) {
+		if err := m.client.DeleteByID(route.ID); err != nil {
+			return fmt.Errorf("删除现有路由失败: %w", err)
+		}
+	}
+	return m.InsertRoute(route, index)
+}
+
 // DeleteByID 删除指定 ID 的路由 - 对应 Python 的 del_id(id) 函数
 // 通过路由 ID 删除特定路由
 func (m *Manager) DeleteByID(id string) error {
@@ -69,101 +206,36 @@ func (m *Manager) DeleteByID(id string) error {
 // AddReverseProxy 添加反向代理路由 - 对应 Python 的 add_reverse_proxy(from_host, to_url) 函数
 // 创建从指定主机到目标 URL 的反向代理
 func (m *Manager) AddReverseProxy(fromHost, toURL string) error {
-	// 如果已存在相同主机的路由，先删除
-	if m.client.HasID(fromHost) {
-		if err := m.client.DeleteByID(fromHost); err != nil {
-			return fmt.Errorf("删除现有路由失败: %w", err)
-		}
-	}
-
-	// 创建反向代理路由配置
-	route := types.Route{
-		ID: fromHost,
-		Handle: []types.Handler{
-			{
-				Handler: "reverse_proxy",
-				Upstreams: []types.Upstream{
-					{
-						Dial: toURL,
-					},
-				},
-			},
-		},
-		Match: []types.RouteMatch{
-			{
-				Host: []string{fromHost},
-			},
-		},
-		Terminal: true, // 设置为终端路由
-	}
-
-	// 添加路由
-	return m.AddRoute(route)
+	return m.AddReverseProxyWithOptions(fromHost, toURL, ProxyOptions{})
 }
 
 // AddWildcardRoute 添加通配符子域名路由 - 对应 Python 的 add_wildcard_route(domain) 函数
-// 为指定域名创建通配符子域名路由
+// 为指定域名创建通配符子域名路由，已存在时不做修改
 func (m *Manager) AddWildcardRoute(domain string) error {
-	// 创建通配符路由配置
-	route := types.Route{
-		ID: fmt.Sprintf("wildcard-%s", domain),
-		Match: []types.RouteMatch{
-			{
-				Host: []string{fmt.Sprintf("*.%s", domain)}, // 通配符匹配
-			},
-		},
-		Handle: []types.Handler{
-			{
-				Handler: "subroute", // 使用子路由处理器
-				Routes:  []types.Route{},
-			},
-		},
-		Terminal: true,
-	}
-
-	// 添加路由
-	return m.AddRoute(route)
+	return m.Domain(domain).Ensure()
 }
 
 // AddSubReverseProxy 添加子域名反向代理 - 对应 Python 的 add_sub_reverse_proxy 函数
 // 为通配符域名下的特定子域名添加反向代理，支持多端口
 func (m *Manager) AddSubReverseProxy(domain, subdomain string, ports []string, host string) error {
-	wildcardID := fmt.Sprintf("wildcard-%s", domain)
-	routeID := fmt.Sprintf("%s.%s", subdomain, domain)
+	return m.AddSubReverseProxyWithOptions(domain, subdomain, ports, host, ProxyOptions{})
+}
 
+// AddSubReverseProxyWithOptions 添加带选项的子域名反向代理
+// 与 AddSubReverseProxy 相同，但支持压缩、头部操作等额外选项；重复调用会更新已有的子域名
+func (m *Manager) AddSubReverseProxyWithOptions(domain, subdomain string, ports []string, host string, opts ProxyOptions) error {
 	// 如果 host 为空，默认使用 localhost
 	if host == "" {
 		host = "localhost"
 	}
 
-	// 构建上游服务器列表
-	var upstreams []types.Upstream
+	// 构建上游地址列表
+	var upstreams []string
 	for _, port := range ports {
-		upstreams = append(upstreams, types.Upstream{
-			Dial: fmt.Sprintf("%s:%s", host, port),
-		})
-	}
-
-	// 创建子路由配置
-	newRoute := types.Route{
-		ID: routeID,
-		Match: []types.RouteMatch{
-			{
-				Host: []string{routeID},
-			},
-		},
-		Handle: []types.Handler{
-			{
-				Handler:   "reverse_proxy",
-				Upstreams: upstreams,
-			},
-		},
+		upstreams = append(upstreams, fmt.Sprintf("%s:%s", host, port))
 	}
 
-	// 将子路由添加到通配符路由的处理器中
-	// 这里使用 "..." 语法来追加到现有路由列表
-	subroutePath := fmt.Sprintf("%s/handle/0/routes/...", wildcardID)
-	return m.client.PutByID([]types.Route{newRoute}, subroutePath, "POST")
+	return m.Domain(domain).SetSubdomain(subdomain, upstreams, opts)
 }
 
 // AddSubReverseProxyWithPorts 添加子域名反向代理（支持单个端口或端口列表）
@@ -0,0 +1,126 @@
+package routes
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/youfun/fastcaddy/pkg/types"
+)
+
+// PHPFastCGIRoute 构建 PHP FastCGI 路由 - 等价于 Caddyfile 中 php_fastcgi 指令的展开
+// fpmAddr 可以是 TCP 地址 (如 "127.0.0.1:9000") 或 Unix 套接字路径 (如 "/run/php/php-fpm.sock")
+func PHPFastCGIRoute(host, root, fpmAddr string) (types.Route, error) {
+	if root == "" {
+		return types.Route{}, fmt.Errorf("必须指定站点根目录")
+	}
+	if fpmAddr == "" {
+		return types.Route{}, fmt.Errorf("必须指定 PHP-FPM 地址")
+	}
+
+	// Unix 套接字使用 Caddy 的 unix/ 网络前缀
+	if strings.HasPrefix(fpmAddr, "/") {
+		fpmAddr = "unix/" + fpmAddr
+	}
+
+	splitPath := []string{".php"}
+
+	routes := []types.Route{
+		// 目录请求缺少结尾斜杠时重定向，保证相对路径正确
+		{
+			Match: []types.RouteMatch{
+				{
+					File: &types.FileMatch{
+						TryFiles: []string{"{http.request.uri.path}/index.php"},
+					},
+					Not: []types.RouteMatch{
+						{
+							Path: []string{"*/"},
+						},
+					},
+				},
+			},
+			Handle: []types.Handler{
+				StaticResponseHandler(http.StatusPermanentRedirect, "", map[string][]string{
+					"Location": {"{http.request.orig_uri.path}/{http.request.orig_uri.prefixed_query}"},
+				}),
+			},
+		},
+		// 不存在的文件交给 index.php 处理 (前端控制器)
+		{
+			Match: []types.RouteMatch{
+				{
+					File: &types.FileMatch{
+						TryFiles:  []string{"{http.request.uri.path}", "{http.request.uri.path}/index.php", "index.php"},
+						SplitPath: splitPath,
+					},
+				},
+			},
+			Handle: []types.Handler{
+				{
+					Handler: "rewrite",
+					URI:     "{http.matchers.file.relative}",
+				},
+			},
+		},
+		// PHP 脚本转发到 PHP-FPM
+		{
+			Match: []types.RouteMatch{
+				{
+					Path: []string{"*.php"},
+				},
+			},
+			Handle: []types.Handler{
+				{
+					Handler: "reverse_proxy",
+					Transport: &types.Transport{
+						Protocol:  "fastcgi",
+						SplitPath: splitPath,
+					},
+					Upstreams: []types.Upstream{
+						{
+							Dial: fpmAddr,
+						},
+					},
+				},
+			},
+		},
+		// 其他静态文件直接提供
+		{
+			Handle: []types.Handler{
+				FileServerHandler(root, FileServerOptions{HideDotfiles: true}),
+			},
+		},
+	}
+
+	return types.Route{
+		ID: host,
+		Match: []types.RouteMatch{
+			{
+				Host: []string{host},
+			},
+		},
+		Handle: []types.Handler{
+			// 设置站点根目录，file 匹配器和 fastcgi 传输默认使用 {http.vars.root}
+			{
+				Handler: "vars",
+				Root:    root,
+			},
+			{
+				Handler: "subroute",
+				Routes:  routes,
+			},
+		},
+		Terminal: true,
+	}, nil
+}
+
+// AddPHPFastCGI 添加 PHP FastCGI 站点路由
+// 为指定主机提供 root 目录下的 PHP 应用，PHP 脚本转发到 fpmAddr 上的 PHP-FPM
+func (m *Manager) AddPHPFastCGI(host, root, fpmAddr string) error {
+	route, err := PHPFastCGIRoute(host, root, fpmAddr)
+	if err != nil {
+		return err
+	}
+	return m.ReplaceRoute(route)
+}
@@ -0,0 +1,131 @@
+package routes
+
+import (
+	"fmt"
+
+	"github.com/youfun/fastcaddy/pkg/types"
+)
+
+// ProxyOptions 反向代理选项
+type ProxyOptions struct {
+	Path            string               // 路径匹配 (如 "/v1/*"，为空时匹配主机下的所有路径)
+	Rewrite         RewriteOptions       // 发送到上游前的请求重写
+	SecureHeaders   bool                 // 是否添加安全响应头预设 (HSTS, X-Frame-Options 等)
+	Compress        bool                 // 是否启用响应压缩 (zstd, gzip)
+	RequestHeaders  *types.HeaderOps     // 发送到上游前的请求头操作
+	ResponseHeaders *types.RespHeaderOps // 返回给客户端前的响应头操作
+	Streaming       *StreamingOptions    // 流式传输选项 (见 ProxyProfile)
+	Transport       *types.Transport     // 与上游通信的传输配置 (TLS、HTTP 版本、超时等)
+	MaxBodySize     int64                // 请求体最大大小 (字节，0 表示不限制)
+	RateLimit       *RateLimitOptions    // 限流选项 (需要 rate_limit 模块)
+}
+
+// ReverseProxyHandler 构建 reverse_proxy 处理器
+func ReverseProxyHandler(upstreams []types.Upstream, opts ProxyOptions) types.Handler {
+	handler := types.Handler{
+		Handler:   "reverse_proxy",
+		Upstreams: upstreams,
+	}
+
+	if opts.RequestHeaders != nil || opts.ResponseHeaders != nil {
+		handler.UpstreamHeaders = &types.HeadersConfig{
+			Request:  opts.RequestHeaders,
+			Response: opts.ResponseHeaders,
+		}
+	}
+
+	if opts.Transport != nil {
+		transport := *opts.Transport
+		if transport.Protocol == "" {
+			transport.Protocol = "http"
+		}
+		handler.Transport = &transport
+	}
+
+	if opts.Streaming != nil {
+		applyStreaming(&handler, *opts.Streaming)
+	}
+
+	return handler
+}
+
+// ProxyHandlers 根据选项构建路由 routeID 的反向代理处理器链
+// 非终端处理器 (如 rewrite, headers) 排在前面，reverse_proxy 始终位于最后；
+// 限流和请求体大小限制最先执行，尽早拒绝超限的请求
+func ProxyHandlers(routeID string, upstreams []types.Upstream, opts ProxyOptions) ([]types.Handler, error) {
+	var handlers []types.Handler
+
+	if opts.RateLimit != nil {
+		rateLimit, err := RateLimitHandler(routeID, *opts.RateLimit)
+		if err != nil {
+			return nil, err
+		}
+		handlers = append(handlers, rateLimit)
+	}
+
+	if opts.MaxBodySize > 0 {
+		handlers = append(handlers, RequestBodyHandler(opts.MaxBodySize))
+	}
+
+	if !opts.Rewrite.IsZero() {
+		rewrite, err := RewriteHandler(opts.Rewrite)
+		if err != nil {
+			return nil, err
+		}
+		handlers = append(handlers, rewrite)
+	}
+
+	if opts.SecureHeaders {
+		handlers = append(handlers, SecurityHeadersHandler())
+	}
+
+	if opts.Compress {
+		handlers = append(handlers, CompressionHandler(CompressionOptions{}))
+	}
+
+	return append(handlers, ReverseProxyHandler(upstreams, opts)), nil
+}
+
+// AddReverseProxyWithOptions 添加带选项的反向代理路由
+// 与 AddReverseProxy 相同，但支持路径匹配、请求重写和头部操作等额外选项。
+// 指定路径的路由会插入到最前面，以便优先于同一主机的整站路由生效
+func (m *Manager) AddReverseProxyWithOptions(fromHost, toURL string, opts ProxyOptions) error {
+	if toURL == "" {
+		return fmt.Errorf("必须指定目标地址")
+	}
+
+	// 解析上游地址，https:// 和 h2c:// 会推导出对应的传输配置
+	upstream, transport, err := ParseUpstream(toURL)
+	if err != nil {
+		return err
+	}
+	opts.Transport = mergeTransport(opts.Transport, transport)
+
+	id := RouteID("", fromHost, opts.Path)
+	handlers, err := ProxyHandlers(id, []types.Upstream{upstream}, opts)
+	if err != nil {
+		return err
+	}
+	if err := m.checkRateLimit(id, opts, handlers); err != nil {
+		return err
+	}
+
+	match := types.RouteMatch{
+		Host: []string{fromHost},
+	}
+	if opts.Path != "" {
+		match.Path = []string{opts.Path}
+	}
+
+	route := types.Route{
+		ID:       id,
+		Handle:   handlers,
+		Match:    []types.RouteMatch{match},
+		Terminal: true, // 设置为终端路由
+	}
+
+	if opts.Path != "" {
+		return m.replaceRouteAt(route, 0)
+	}
+	return m.ReplaceRoute(route)
+}
@@ -0,0 +1,183 @@
+package routes
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/youfun/fastcaddy/internal/utils"
+	"github.com/youfun/fastcaddy/pkg/types"
+)
+
+// StaticResponseOptions 静态响应选项
+type StaticResponseOptions struct {
+	Path       string              // 路径匹配 (为空时匹配主机下的所有路径)
+	StatusCode int                 // 响应状态码 (默认: 200)
+	Body       string              // 响应内容
+	Headers    map[string][]string // 响应头
+}
+
+// Redirect 重定向规则
+type Redirect struct {
+	From string // 来源，可以是主机名或主机名加路径 (如 old.example.com/blog/*)
+	To   string // 目标地址，支持占位符 (如 https://new.example.com{uri})
+	Code int    // 重定向状态码 (默认: 302)
+}
+
+// StaticResponseHandler 构建 static_response 处理器
+func StaticResponseHandler(statusCode int, body string, headers map[string][]string) types.Handler {
+	handler := types.Handler{
+		Handler: "static_response",
+		Body:    body,
+		Headers: headers,
+	}
+	if statusCode != 0 {
+		handler.StatusCode = types.WeakString(strconv.Itoa(statusCode))
+	}
+	return handler
+}
+
+// StaticResponseRouteID 生成静态响应路由的 ID
+func StaticResponseRouteID(host, path string) string {
+	return RouteID("static", host, path)
+}
+
+// RedirectRouteID 生成重定向路由的 ID (来源地址中的路径经 RouteID 转义，可以通过 /id/ 访问)
+func RedirectRouteID(from string) string {
+	host, path := from, ""
+	if i := strings.Index(from, "/"); i >= 0 {
+		host, path = from[:i], from[i:]
+	}
+	return RouteID("redirect", host, path)
+}
+
+// AddStaticResponse 添加静态响应路由
+// 路由插入到最前面，以便优先于同一主机的反向代理等路由生效
+func (m *Manager) AddStaticResponse(host string, opts StaticResponseOptions) error {
+	match := types.RouteMatch{
+		Host: []string{host},
+	}
+	if opts.Path != "" {
+		match.Path = []string{opts.Path}
+	}
+
+	route := types.Route{
+		ID:       StaticResponseRouteID(host, opts.Path),
+		Match:    []types.RouteMatch{match},
+		Handle:   []types.Handler{StaticResponseHandler(opts.StatusCode, opts.Body, opts.Headers)},
+		Terminal: true,
+	}
+
+	return m.replaceRouteAt(route, 0)
+}
+
+// AddRedirect 添加重定向路由
+// from 可以是主机名 (old.example.com) 或主机名加路径 (old.example.com/blog/*)，
+// to 支持 Caddyfile 风格的占位符简写 (如 {uri}, {path})
+func (m *Manager) AddRedirect(from, to string, code int) error {
+	route, err := RedirectRoute(Redirect{From: from, To: to, Code: code})
+	if err != nil {
+		return err
+	}
+	return m.replaceRouteAt(route, 0)
+}
+
+// AddRedirects 批量添加重定向路由，遇到错误时立即返回
+func (m *Manager) AddRedirects(redirects []Redirect) error {
+	for _, redirect := range redirects {
+		if err := m.AddRedirect(redirect.From, redirect.To, redirect.Code); err != nil {
+			return fmt.Errorf("添加重定向 %s 失败: %w", redirect.From, err)
+		}
+	}
+	return nil
+}
+
+// RedirectRoute 根据重定向规则构建路由
+func RedirectRoute(redirect Redirect) (types.Route, error) {
+	if redirect.Code == 0 {
+		redirect.Code = http.StatusFound
+	}
+	if redirect.Code < 300 || redirect.Code > 399 {
+		return types.Route{}, fmt.Errorf("无效的重定向状态码: %d", redirect.Code)
+	}
+	if redirect.To == "" {
+		return types.Route{}, fmt.Errorf("必须指定重定向目标")
+	}
+
+	// 拆分主机名和路径
+	host, path := redirect.From, ""
+	if i := strings.Index(redirect.From, "/"); i >= 0 {
+		host, path = redirect.From[:i], redirect.From[i:]
+	}
+	if !utils.ValidateHost(host) {
+		return types.Route{}, fmt.Errorf("无效的主机名: %s", host)
+	}
+
+	match := types.RouteMatch{
+		Host: []string{host},
+	}
+	if path != "" {
+		match.Path = []string{path}
+	}
+
+	headers := map[string][]string{
+		"Location": {utils.ExpandPlaceholders(redirect.To)},
+	}
+
+	return types.Route{
+		ID:       RedirectRouteID(redirect.From),
+		Match:    []types.RouteMatch{match},
+		Handle:   []types.Handler{StaticResponseHandler(redirect.Code, "", headers)},
+		Terminal: true,
+	}, nil
+}
+
+// ParseRedirectsCSV 从 CSV 读取重定向规则
+// 每行格式为 from,to[,code]，以 '#' 开头的行和 "from,to" 表头会被忽略
+func ParseRedirectsCSV(r io.Reader) ([]Redirect, error) {
+	reader := csv.NewReader(r)
+	reader.Comment = '#'
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	var redirects []Redirect
+	for first := true; ; first = false {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("解析 CSV 失败: %w", err)
+		}
+
+		line, _ := reader.FieldPos(0)
+
+		// 跳过表头
+		if first && strings.EqualFold(strings.TrimSpace(record[0]), "from") {
+			continue
+		}
+
+		if len(record) < 2 {
+			return nil, fmt.Errorf("第 %d 行: 至少需要 from 和 to 两列", line)
+		}
+
+		redirect := Redirect{
+			From: strings.TrimSpace(record[0]),
+			To:   strings.TrimSpace(record[1]),
+		}
+		if len(record) > 2 && strings.TrimSpace(record[2]) != "" {
+			code, err := strconv.Atoi(strings.TrimSpace(record[2]))
+			if err != nil {
+				return nil, fmt.Errorf("第 %d 行: 无效的状态码 %s", line, record[2])
+			}
+			redirect.Code = code
+		}
+
+		redirects = append(redirects, redirect)
+	}
+
+	return redirects, nil
+}
@@ -0,0 +1,42 @@
+package routes
+
+import (
+	"fmt"
+	"regexp"
+
+	"github.com/youfun/fastcaddy/pkg/types"
+)
+
+// RewriteOptions 请求重写选项
+// 多个选项同时设置时按 Caddy 的顺序执行: uri -> 去除前缀/后缀 -> 子串替换 -> 正则替换
+type RewriteOptions struct {
+	URI         string                    // 重写整个 URI (支持占位符，如 "/index.php?{query}")
+	StripPrefix string                    // 去除的路径前缀 (如 "/v1")
+	StripSuffix string                    // 去除的路径后缀 (如 ".html")
+	Replace     []types.SubstringReplacer // URI 子串替换
+	PathRegexp  []types.RegexReplacer     // 路径正则替换
+}
+
+// IsZero 判断是否未设置任何重写选项
+func (o RewriteOptions) IsZero() bool {
+	return o.URI == "" && o.StripPrefix == "" && o.StripSuffix == "" &&
+		len(o.Replace) == 0 && len(o.PathRegexp) == 0
+}
+
+// RewriteHandler 构建 rewrite 处理器
+func RewriteHandler(opts RewriteOptions) (types.Handler, error) {
+	for _, re := range opts.PathRegexp {
+		if _, err := regexp.Compile(re.Find); err != nil {
+			return types.Handler{}, fmt.Errorf("无效的路径正则 %q: %w", re.Find, err)
+		}
+	}
+
+	return types.Handler{
+		Handler:         "rewrite",
+		URI:             opts.URI,
+		StripPathPrefix: opts.StripPrefix,
+		StripPathSuffix: opts.StripSuffix,
+		URISubstring:    opts.Replace,
+		PathRegexp:      opts.PathRegexp,
+	}, nil
+}
@@ -0,0 +1,70 @@
+package routes
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/youfun/fastcaddy/pkg/types"
+)
+
+// StreamingOptions 流式传输选项 - 适用于 WebSocket、SSE 等长连接服务
+type StreamingOptions struct {
+	FlushInterval      types.Duration // 响应刷新间隔 ("-1" 表示立即刷新)
+	StreamTimeout      types.Duration // 长连接流的最长持续时间 (如 "24h")
+	StreamCloseDelay   types.Duration // 配置重载时延迟关闭长连接的时间，避免每次变更配置都断开所有连接
+	RequestBuffers     int64          // 请求体缓冲大小 (字节，0 表示不缓冲)
+	ResponseBuffers    int64          // 响应体缓冲大小 (字节，0 表示不缓冲)
+	DisableCompression bool           // 是否禁止向上游请求压缩响应 (压缩会导致事件流被缓冲)
+}
+
+// proxyProfiles 预置的流式传输配置
+var proxyProfiles = map[string]StreamingOptions{
+	// WebSocket: 立即刷新，允许长时间连接，配置重载时保留连接
+	"websocket": {
+		FlushInterval:    "-1",
+		StreamTimeout:    "24h",
+		StreamCloseDelay: "5m",
+	},
+	// Server-Sent Events: 立即刷新，不压缩上游响应
+	"sse": {
+		FlushInterval:      "-1",
+		StreamCloseDelay:   "5m",
+		DisableCompression: true,
+	},
+}
+
+// ProxyProfile 获取预置的流式传输配置
+func ProxyProfile(name string) (StreamingOptions, error) {
+	profile, ok := proxyProfiles[name]
+	if !ok {
+		return StreamingOptions{}, fmt.Errorf("未知的代理配置: %s (可选: %v)", name, ProxyProfileNames())
+	}
+	return profile, nil
+}
+
+// ProxyProfileNames 返回所有预置配置的名称
+func ProxyProfileNames() []string {
+	names := make([]string, 0, len(proxyProfiles))
+	for name := range proxyProfiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// applyStreaming 将流式传输选项应用到 reverse_proxy 处理器
+func applyStreaming(handler *types.Handler, opts StreamingOptions) {
+	handler.FlushInterval = opts.FlushInterval
+	handler.StreamTimeout = opts.StreamTimeout
+	handler.StreamCloseDelay = opts.StreamCloseDelay
+	handler.RequestBuffers = opts.RequestBuffers
+	handler.ResponseBuffers = opts.ResponseBuffers
+
+	if opts.DisableCompression {
+		if handler.Transport == nil {
+			handler.Transport = &types.Transport{Protocol: "http"}
+		}
+		compression := false
+		handler.Transport.Compression = &compression
+	}
+}
@@ -0,0 +1,78 @@
+package routes
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+
+	"github.com/youfun/fastcaddy/pkg/types"
+)
+
+// ParseUpstream 解析上游地址
+// 支持 host:port、http://host[:port]、https://host[:port] 和 h2c://host:port，
+// https 会生成启用 TLS 的传输配置，h2c 会生成 HTTP/2 明文传输配置 (如 gRPC 后端)
+func ParseUpstream(address string) (types.Upstream, *types.Transport, error) {
+	if !strings.Contains(address, "://") {
+		return types.Upstream{Dial: address}, nil, nil
+	}
+
+	u, err := url.Parse(address)
+	if err != nil {
+		return types.Upstream{}, nil, fmt.Errorf("无效的上游地址 %s: %w", address, err)
+	}
+	if u.Host == "" {
+		return types.Upstream{}, nil, fmt.Errorf("无效的上游地址 %s: 缺少主机名", address)
+	}
+	if u.Path != "" && u.Path != "/" {
+		return types.Upstream{}, nil, fmt.Errorf("上游地址不能包含路径: %s (请使用重写选项)", address)
+	}
+
+	var defaultPort string
+	var transport *types.Transport
+	switch u.Scheme {
+	case "http":
+		defaultPort = "80"
+	case "https":
+		defaultPort = "443"
+		transport = &types.Transport{
+			Protocol: "http",
+			TLS:      &types.TransportTLS{},
+		}
+	case "h2c":
+		defaultPort = "80"
+		transport = &types.Transport{
+			Protocol: "http",
+			Versions: []string{"h2c", "2"},
+		}
+	default:
+		return types.Upstream{}, nil, fmt.Errorf("不支持的上游协议: %s", u.Scheme)
+	}
+
+	dial := u.Host
+	if u.Port() == "" {
+		dial = net.JoinHostPort(u.Hostname(), defaultPort)
+	}
+
+	return types.Upstream{Dial: dial}, transport, nil
+}
+
+// mergeTransport 将上游地址推导出的传输配置合并到用户指定的传输配置
+// 用户显式设置的字段优先
+func mergeTransport(base, derived *types.Transport) *types.Transport {
+	if base == nil {
+		return derived
+	}
+	if derived == nil {
+		return base
+	}
+
+	merged := *base
+	if merged.TLS == nil {
+		merged.TLS = derived.TLS
+	}
+	if len(merged.Versions) == 0 {
+		merged.Versions = derived.Versions
+	}
+	return &merged
+}
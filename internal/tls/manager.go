@@ -23,24 +23,28 @@ func NewManager() *Manager {
 	}
 }
 
-// GetACMEConfig 获取 ACME 配置 - 对应 Python 的 get_acme_config(token) 函数
-// 创建用于 Cloudflare DNS 挑战的 ACME 配置
-func GetACMEConfig(token string) map[string]interface{} {
-	provider := map[string]interface{}{
-		"name":      "cloudflare",
-		"api_token": token,
+// NewManagerWithClient 使用指定的 API 客户端创建 TLS 管理器
+func NewManagerWithClient(client *api.Client) *Manager {
+	return &Manager{
+		client:        client,
+		configManager: config.NewManagerWithClient(client),
 	}
+}
 
-	challenges := map[string]interface{}{
-		"dns": map[string]interface{}{
-			"provider": provider,
+// GetACMEConfig 获取 ACME 配置 - 对应 Python 的 get_acme_config(token) 函数
+// 创建用于 Cloudflare DNS 挑战的 ACME 配置
+func GetACMEConfig(token string) types.TLSIssuer {
+	return types.TLSIssuer{
+		Module: "acme",
+		Challenges: &types.ACMEChallenges{
+			DNS: &types.DNSChallenge{
+				Provider: &types.ACMEProvider{
+					Name:     "cloudflare",
+					APIToken: token,
+				},
+			},
 		},
 	}
-
-	return map[string]interface{}{
-		"module":     "acme",
-		"challenges": challenges,
-	}
 }
 
 // AddTLSInternalConfig 添加内部 TLS 配置 - 对应 Python 的 add_tls_internal_config() 函数
@@ -52,7 +56,7 @@ func (m *Manager) AddTLSInternalConfig() error {
 	}
 
 	// 创建空的根配置
-	if err := m.client.PutConfig(map[string]interface{}{}, "/", "POST"); err != nil {
+	if err := m.client.PutConfig(types.CaddyConfig{}, "/", "POST"); err != nil {
 		return err
 	}
 
@@ -62,12 +66,10 @@ func (m *Manager) AddTLSInternalConfig() error {
 	}
 
 	// 创建内部证书颁发者策略
-	policies := []map[string]interface{}{
+	policies := []types.TLSAutomationPolicy{
 		{
-			"issuers": []map[string]interface{}{
-				{
-					"module": "internal",
-				},
+			Issuers: []types.TLSIssuer{
+				{Module: "internal"},
 			},
 		},
 	}
@@ -86,7 +88,7 @@ func (m *Manager) AddACMEConfig(cfToken string) error {
 	}
 
 	// 创建空的根配置
-	if err := m.client.PutConfig(map[string]interface{}{}, "/", "POST"); err != nil {
+	if err := m.client.PutConfig(types.CaddyConfig{}, "/", "POST"); err != nil {
 		return err
 	}
 
@@ -97,12 +99,12 @@ func (m *Manager) AddACMEConfig(cfToken string) error {
 
 	// 创建 ACME 配置
 	acmeConfig := GetACMEConfig(cfToken)
-	issuers := []map[string]interface{}{acmeConfig}
+	issuers := []types.TLSIssuer{acmeConfig}
 
 	// 创建 ACME 策略
-	policies := []map[string]interface{}{
+	policies := []types.TLSAutomationPolicy{
 		{
-			"issuers": issuers,
+			Issuers: issuers,
 		},
 	}
 
@@ -129,7 +131,7 @@ func (m *Manager) SetupPKITrust(installTrust *bool) error {
 
 	// 创建 PKI 配置
 	pkiConfig := types.PKIConfig{
-		InstallTrust: *installTrust,
+		InstallTrust: installTrust,
 	}
 
 	// 设置 PKI 配置
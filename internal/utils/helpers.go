@@ -1,7 +1,10 @@
 package utils
 
 import (
+	"fmt"
+	"net"
 	"os"
+	"strconv"
 	"strings"
 )
 
@@ -123,6 +126,85 @@ func ValidateURL(url string) bool {
 	return true
 }
 
+// ValidateCIDR 验证 IP 地址或 CIDR 范围格式
+// 接受单个 IP (如 "10.0.0.1") 或 CIDR (如 "10.0.0.0/8")
+func ValidateCIDR(value string) bool {
+	if strings.Contains(value, "/") {
+		_, _, err := net.ParseCIDR(value)
+		return err == nil
+	}
+	return net.ParseIP(value) != nil
+}
+
+// ParseSize 解析字节大小 (如 "512", "10KB", "10MB", "1GB")
+// 单位不区分大小写，按 1024 进制计算
+func ParseSize(value string) (int64, error) {
+	s := strings.ToUpper(strings.TrimSpace(value))
+	units := []struct {
+		suffix string
+		factor int64
+	}{
+		{"GB", 1 << 30}, {"MB", 1 << 20}, {"KB", 1 << 10},
+		{"G", 1 << 30}, {"M", 1 << 20}, {"K", 1 << 10}, {"B", 1},
+	}
+
+	factor := int64(1)
+	for _, unit := range units {
+		if strings.HasSuffix(s, unit.suffix) {
+			s = strings.TrimSpace(strings.TrimSuffix(s, unit.suffix))
+			factor = unit.factor
+			break
+		}
+	}
+
+	n, err := strconv.ParseInt(s, 10, 64)
+	if err != nil || n <= 0 {
+		return 0, fmt.Errorf("无效的大小: %s", value)
+	}
+	return n * factor, nil
+}
+
+// ValidateProtocol 验证 HTTP 服务器协议名称
+// Caddy 支持的协议: h1, h2, h2c, h3
+func ValidateProtocol(protocol string) bool {
+	switch protocol {
+	case "h1", "h2", "h2c", "h3":
+		return true
+	}
+	return false
+}
+
+// SplitList 将逗号分隔的字符串拆分为列表
+// 去除每项首尾空白并忽略空项
+func SplitList(value string) []string {
+	var result []string
+	for _, item := range strings.Split(value, ",") {
+		item = strings.TrimSpace(item)
+		if item != "" {
+			result = append(result, item)
+		}
+	}
+	return result
+}
+
+// placeholderShorthands Caddyfile 占位符简写到完整占位符的映射
+var placeholderShorthands = strings.NewReplacer(
+	"{uri}", "{http.request.uri}",
+	"{path}", "{http.request.uri.path}",
+	"{query}", "{http.request.uri.query}",
+	"{host}", "{http.request.host}",
+	"{hostport}", "{http.request.hostport}",
+	"{scheme}", "{http.request.scheme}",
+	"{method}", "{http.request.method}",
+	"{remote_host}", "{http.request.remote.host}",
+)
+
+// ExpandPlaceholders 展开 Caddyfile 风格的占位符简写
+// 例如 "https://example.com{uri}" -> "https://example.com{http.request.uri}"
+func ExpandPlaceholders(value string) string {
+	return placeholderShorthands.Replace(value)
+}
+
 // DefaultIfEmpty 如果值为空则返回默认值
 func DefaultIfEmpty(value, defaultValue string) string {
 	if value == "" {
@@ -0,0 +1,49 @@
+package types
+
+// 应用配置 - apps 下的各个应用，未建模的应用 (如 layer4、events) 保留在 Extra 中
+type Apps struct {
+	HTTP  *HTTPApp `json:"http,omitempty"` // HTTP 应用
+	TLS   *TLSApp  `json:"tls,omitempty"`  // TLS 应用
+	PKI   *PKIApp  `json:"pki,omitempty"`  // PKI 应用
+	Extra Extra    `json:"-"`              // 其他应用 (应用名 -> 原始 JSON)
+}
+
+// HTTP 应用 - 定义 HTTP 服务器列表和全局端口
+type HTTPApp struct {
+	HTTPPort    int                    `json:"http_port,omitempty"`    // HTTP 端口 (默认 80)
+	HTTPSPort   int                    `json:"https_port,omitempty"`   // HTTPS 端口 (默认 443)
+	GracePeriod Duration               `json:"grace_period,omitempty"` // 关闭服务器时等待连接结束的时长
+	Servers     map[string]*HTTPServer `json:"servers,omitempty"`      // 服务器名称 -> 服务器配置
+	Extra       Extra                  `json:"-"`                      // 未建模的字段
+}
+
+// TLS 应用 - 定义证书自动化配置
+type TLSApp struct {
+	Automation *TLSAutomation `json:"automation,omitempty"` // 证书自动化配置
+	Extra      Extra          `json:"-"`                    // 未建模的字段 (如 certificates、session_tickets)
+}
+
+// TLS 自动化配置 - 定义证书自动化策略
+type TLSAutomation struct {
+	Policies []TLSAutomationPolicy `json:"policies,omitempty"` // 自动化策略列表 (按顺序匹配主体)
+	Extra    Extra                 `json:"-"`                  // 未建模的字段 (如 on_demand)
+}
+
+// ACME 挑战配置 - 定义证书颁发者使用的挑战类型
+type ACMEChallenges struct {
+	DNS   *DNSChallenge `json:"dns,omitempty"` // DNS-01 挑战
+	Extra Extra         `json:"-"`             // 未建模的字段 (如 http、tls-alpn)
+}
+
+// DNS 挑战配置 - 定义 DNS 提供商和解析器
+type DNSChallenge struct {
+	Provider  *ACMEProvider `json:"provider,omitempty"`  // DNS 提供商
+	Resolvers []string      `json:"resolvers,omitempty"` // 检查 DNS 记录传播使用的解析器
+	Extra     Extra         `json:"-"`                   // 未建模的字段
+}
+
+// PKI 应用 - 定义内部证书颁发机构
+type PKIApp struct {
+	CertificateAuthorities map[string]*PKIConfig `json:"certificate_authorities,omitempty"` // CA 名称 (如 "local") -> CA 配置
+	Extra                  Extra                 `json:"-"`                                 // 未建模的字段
+}
@@ -0,0 +1,656 @@
+package types
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// Extra 未建模的 JSON 字段 - 反序列化时原样保留，序列化时写回，
+// 使类型化的配置在读取、修改、写回的过程中不会丢失 fastcaddy 未声明的选项
+type Extra map[string]json.RawMessage
+
+// knownFieldsCache 结构体类型 -> 已声明的 JSON 字段名
+var knownFieldsCache sync.Map
+
+// knownFields 返回结构体类型声明的 JSON 字段名 (包括匿名嵌入结构体的字段)
+func knownFields(t reflect.Type) map[string]bool {
+	if cached, ok := knownFieldsCache.Load(t); ok {
+		return cached.(map[string]bool)
+	}
+
+	fields := make(map[string]bool)
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, _, _ := strings.Cut(tag, ",")
+		if field.Anonymous && name == "" && field.Type.Kind() == reflect.Struct {
+			for key := range knownFields(field.Type) {
+				fields[key] = true
+			}
+			continue
+		}
+		if !field.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		fields[name] = true
+	}
+
+	knownFieldsCache.Store(t, fields)
+	return fields
+}
+
+// unknownFields 返回 JSON 对象中结构体类型 t 未声明的字段
+func unknownFields(data []byte, t reflect.Type) (Extra, error) {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+
+	known := knownFields(t)
+	var extra Extra
+	for key, raw := range fields {
+		if known[key] {
+			continue
+		}
+		if extra == nil {
+			extra = make(Extra)
+		}
+		extra[key] = raw
+	}
+	return extra, nil
+}
+
+// marshalExtra 序列化 v 并合并未建模的字段 (已声明的字段优先)
+func marshalExtra(v interface{}, extra Extra) ([]byte, error) {
+	data, err := json.Marshal(v)
+	if err != nil || len(extra) == 0 {
+		return data, err
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+	for key, raw := range extra {
+		if _, ok := fields[key]; !ok {
+			fields[key] = raw
+		}
+	}
+	return json.Marshal(fields)
+}
+
+// unmarshalExtra 反序列化到 v (不带自定义方法的结构体指针)，并将未声明的字段保存到 extra
+func unmarshalExtra(data []byte, v interface{}, extra *Extra) error {
+	if err := json.Unmarshal(data, v); err != nil {
+		return err
+	}
+	fields, err := unknownFields(data, reflect.TypeOf(v).Elem())
+	if err != nil {
+		return err
+	}
+	*extra = fields
+	return nil
+}
+
+// 以下类型与对应的配置结构字段相同但不带自定义序列化方法，避免递归调用
+type (
+	caddyConfigJSON         CaddyConfig
+	appsJSON                Apps
+	httpAppJSON             HTTPApp
+	httpServerJSON          HTTPServer
+	routeJSON               Route
+	routeMatchJSON          RouteMatch
+	upstreamJSON            Upstream
+	transportJSON           Transport
+	tlsAppJSON              TLSApp
+	tlsAutomationJSON       TLSAutomation
+	tlsAutomationPolicyJSON TLSAutomationPolicy
+	tlsIssuerJSON           TLSIssuer
+	acmeChallengesJSON      ACMEChallenges
+	dnsChallengeJSON        DNSChallenge
+	acmeProviderJSON        ACMEProvider
+	pkiAppJSON              PKIApp
+	pkiConfigJSON           PKIConfig
+	loggingConfigJSON       LoggingConfig
+	customLogJSON           CustomLog
+	logWriterJSON           LogWriter
+	logEncoderJSON          LogEncoder
+	ipMatchJSON             IPMatch
+	fileMatchJSON           FileMatch
+	headersConfigJSON       HeadersConfig
+	proxyRewriteJSON        ProxyRewrite
+	responseHandlerJSON     ResponseHandler
+	substringReplacerJSON   SubstringReplacer
+	regexReplacerJSON       RegexReplacer
+	encodingJSON            Encoding
+	responseMatchJSON       ResponseMatch
+	authProvidersJSON       AuthProviders
+	httpBasicAuthJSON       HTTPBasicAuth
+	basicAuthAccountJSON    BasicAuthAccount
+	basicAuthHashJSON       BasicAuthHash
+	headerOpsJSON           HeaderOps
+	replacementJSON         Replacement
+	transportTLSJSON        TransportTLS
+	caPoolJSON              CAPool
+	keepAliveJSON           KeepAlive
+	rateLimitZoneJSON       RateLimitZone
+	fileBrowseJSON          FileBrowse
+	loadBalancingJSON       LoadBalancing
+	selectionPolicyJSON     SelectionPolicy
+	serverLogsJSON          ServerLogs
+	httpErrorConfigJSON     HTTPErrorConfig
+	listenerWrapperJSON     ListenerWrapper
+	trustedProxiesJSON      TrustedProxies
+)
+
+// MarshalJSON 序列化完整配置
+func (c CaddyConfig) MarshalJSON() ([]byte, error) {
+	return marshalExtra(caddyConfigJSON(c), c.Extra)
+}
+
+// UnmarshalJSON 反序列化完整配置，保留未建模的字段 (如 admin、storage)
+func (c *CaddyConfig) UnmarshalJSON(data []byte) error {
+	return unmarshalExtra(data, (*caddyConfigJSON)(c), &c.Extra)
+}
+
+// MarshalJSON 序列化应用配置
+func (a Apps) MarshalJSON() ([]byte, error) {
+	return marshalExtra(appsJSON(a), a.Extra)
+}
+
+// UnmarshalJSON 反序列化应用配置，其他应用 (如 layer4) 保留在 Extra 中
+func (a *Apps) UnmarshalJSON(data []byte) error {
+	return unmarshalExtra(data, (*appsJSON)(a), &a.Extra)
+}
+
+// MarshalJSON 序列化 HTTP 应用
+func (a HTTPApp) MarshalJSON() ([]byte, error) {
+	return marshalExtra(httpAppJSON(a), a.Extra)
+}
+
+// UnmarshalJSON 反序列化 HTTP 应用
+func (a *HTTPApp) UnmarshalJSON(data []byte) error {
+	return unmarshalExtra(data, (*httpAppJSON)(a), &a.Extra)
+}
+
+// MarshalJSON 序列化 HTTP 服务器
+func (s HTTPServer) MarshalJSON() ([]byte, error) {
+	return marshalExtra(httpServerJSON(s), s.Extra)
+}
+
+// UnmarshalJSON 反序列化 HTTP 服务器
+func (s *HTTPServer) UnmarshalJSON(data []byte) error {
+	return unmarshalExtra(data, (*httpServerJSON)(s), &s.Extra)
+}
+
+// MarshalJSON 序列化路由
+func (r Route) MarshalJSON() ([]byte, error) {
+	return marshalExtra(routeJSON(r), r.Extra)
+}
+
+// UnmarshalJSON 反序列化路由
+func (r *Route) UnmarshalJSON(data []byte) error {
+	return unmarshalExtra(data, (*routeJSON)(r), &r.Extra)
+}
+
+// MarshalJSON 序列化匹配集
+func (m RouteMatch) MarshalJSON() ([]byte, error) {
+	return marshalExtra(routeMatchJSON(m), m.Extra)
+}
+
+// UnmarshalJSON 反序列化匹配集，未建模的匹配器 (如 query、protocol) 保留在 Extra 中
+func (m *RouteMatch) UnmarshalJSON(data []byte) error {
+	return unmarshalExtra(data, (*routeMatchJSON)(m), &m.Extra)
+}
+
+// MarshalJSON 序列化上游服务器
+func (u Upstream) MarshalJSON() ([]byte, error) {
+	return marshalExtra(upstreamJSON(u), u.Extra)
+}
+
+// UnmarshalJSON 反序列化上游服务器
+func (u *Upstream) UnmarshalJSON(data []byte) error {
+	return unmarshalExtra(data, (*upstreamJSON)(u), &u.Extra)
+}
+
+// MarshalJSON 序列化传输配置
+func (t Transport) MarshalJSON() ([]byte, error) {
+	return marshalExtra(transportJSON(t), t.Extra)
+}
+
+// UnmarshalJSON 反序列化传输配置
+func (t *Transport) UnmarshalJSON(data []byte) error {
+	return unmarshalExtra(data, (*transportJSON)(t), &t.Extra)
+}
+
+// MarshalJSON 序列化 TLS 应用
+func (a TLSApp) MarshalJSON() ([]byte, error) {
+	return marshalExtra(tlsAppJSON(a), a.Extra)
+}
+
+// UnmarshalJSON 反序列化 TLS 应用
+func (a *TLSApp) UnmarshalJSON(data []byte) error {
+	return unmarshalExtra(data, (*tlsAppJSON)(a), &a.Extra)
+}
+
+// MarshalJSON 序列化 TLS 自动化配置
+func (a TLSAutomation) MarshalJSON() ([]byte, error) {
+	return marshalExtra(tlsAutomationJSON(a), a.Extra)
+}
+
+// UnmarshalJSON 反序列化 TLS 自动化配置
+func (a *TLSAutomation) UnmarshalJSON(data []byte) error {
+	return unmarshalExtra(data, (*tlsAutomationJSON)(a), &a.Extra)
+}
+
+// MarshalJSON 序列化 TLS 自动化策略
+func (p TLSAutomationPolicy) MarshalJSON() ([]byte, error) {
+	return marshalExtra(tlsAutomationPolicyJSON(p), p.Extra)
+}
+
+// UnmarshalJSON 反序列化 TLS 自动化策略
+func (p *TLSAutomationPolicy) UnmarshalJSON(data []byte) error {
+	return unmarshalExtra(data, (*tlsAutomationPolicyJSON)(p), &p.Extra)
+}
+
+// MarshalJSON 序列化证书颁发者
+func (i TLSIssuer) MarshalJSON() ([]byte, error) {
+	return marshalExtra(tlsIssuerJSON(i), i.Extra)
+}
+
+// UnmarshalJSON 反序列化证书颁发者
+func (i *TLSIssuer) UnmarshalJSON(data []byte) error {
+	return unmarshalExtra(data, (*tlsIssuerJSON)(i), &i.Extra)
+}
+
+// MarshalJSON 序列化 ACME 挑战配置
+func (c ACMEChallenges) MarshalJSON() ([]byte, error) {
+	return marshalExtra(acmeChallengesJSON(c), c.Extra)
+}
+
+// UnmarshalJSON 反序列化 ACME 挑战配置
+func (c *ACMEChallenges) UnmarshalJSON(data []byte) error {
+	return unmarshalExtra(data, (*acmeChallengesJSON)(c), &c.Extra)
+}
+
+// MarshalJSON 序列化 DNS 挑战配置
+func (c DNSChallenge) MarshalJSON() ([]byte, error) {
+	return marshalExtra(dnsChallengeJSON(c), c.Extra)
+}
+
+// UnmarshalJSON 反序列化 DNS 挑战配置
+func (c *DNSChallenge) UnmarshalJSON(data []byte) error {
+	return unmarshalExtra(data, (*dnsChallengeJSON)(c), &c.Extra)
+}
+
+// MarshalJSON 序列化 DNS 提供商配置
+func (p ACMEProvider) MarshalJSON() ([]byte, error) {
+	return marshalExtra(acmeProviderJSON(p), p.Extra)
+}
+
+// UnmarshalJSON 反序列化 DNS 提供商配置，其他提供商的凭据字段保留在 Extra 中
+func (p *ACMEProvider) UnmarshalJSON(data []byte) error {
+	return unmarshalExtra(data, (*acmeProviderJSON)(p), &p.Extra)
+}
+
+// MarshalJSON 序列化 PKI 应用
+func (a PKIApp) MarshalJSON() ([]byte, error) {
+	return marshalExtra(pkiAppJSON(a), a.Extra)
+}
+
+// UnmarshalJSON 反序列化 PKI 应用
+func (a *PKIApp) UnmarshalJSON(data []byte) error {
+	return unmarshalExtra(data, (*pkiAppJSON)(a), &a.Extra)
+}
+
+// MarshalJSON 序列化证书颁发机构配置
+func (c PKIConfig) MarshalJSON() ([]byte, error) {
+	return marshalExtra(pkiConfigJSON(c), c.Extra)
+}
+
+// UnmarshalJSON 反序列化证书颁发机构配置
+func (c *PKIConfig) UnmarshalJSON(data []byte) error {
+	return unmarshalExtra(data, (*pkiConfigJSON)(c), &c.Extra)
+}
+
+// MarshalJSON 序列化日志配置
+func (c LoggingConfig) MarshalJSON() ([]byte, error) {
+	return marshalExtra(loggingConfigJSON(c), c.Extra)
+}
+
+// UnmarshalJSON 反序列化日志配置
+func (c *LoggingConfig) UnmarshalJSON(data []byte) error {
+	return unmarshalExtra(data, (*loggingConfigJSON)(c), &c.Extra)
+}
+
+// MarshalJSON 序列化自定义日志
+func (l CustomLog) MarshalJSON() ([]byte, error) {
+	return marshalExtra(customLogJSON(l), l.Extra)
+}
+
+// UnmarshalJSON 反序列化自定义日志
+func (l *CustomLog) UnmarshalJSON(data []byte) error {
+	return unmarshalExtra(data, (*customLogJSON)(l), &l.Extra)
+}
+
+// MarshalJSON 序列化日志输出
+func (w LogWriter) MarshalJSON() ([]byte, error) {
+	return marshalExtra(logWriterJSON(w), w.Extra)
+}
+
+// UnmarshalJSON 反序列化日志输出
+func (w *LogWriter) UnmarshalJSON(data []byte) error {
+	return unmarshalExtra(data, (*logWriterJSON)(w), &w.Extra)
+}
+
+// MarshalJSON 序列化日志编码格式
+func (e LogEncoder) MarshalJSON() ([]byte, error) {
+	return marshalExtra(logEncoderJSON(e), e.Extra)
+}
+
+// UnmarshalJSON 反序列化日志编码格式
+func (e *LogEncoder) UnmarshalJSON(data []byte) error {
+	return unmarshalExtra(data, (*logEncoderJSON)(e), &e.Extra)
+}
+
+// MarshalJSON 序列化IP 匹配规则
+func (r IPMatch) MarshalJSON() ([]byte, error) {
+	return marshalExtra(ipMatchJSON(r), r.Extra)
+}
+
+// UnmarshalJSON 反序列化IP 匹配规则，保留未建模的字段
+func (r *IPMatch) UnmarshalJSON(data []byte) error {
+	return unmarshalExtra(data, (*ipMatchJSON)(r), &r.Extra)
+}
+
+// MarshalJSON 序列化文件匹配规则
+func (f FileMatch) MarshalJSON() ([]byte, error) {
+	return marshalExtra(fileMatchJSON(f), f.Extra)
+}
+
+// UnmarshalJSON 反序列化文件匹配规则，保留未建模的字段 (如 try_policy)
+func (f *FileMatch) UnmarshalJSON(data []byte) error {
+	return unmarshalExtra(data, (*fileMatchJSON)(f), &f.Extra)
+}
+
+// MarshalJSON 序列化请求/响应头配置
+func (h HeadersConfig) MarshalJSON() ([]byte, error) {
+	return marshalExtra(headersConfigJSON(h), h.Extra)
+}
+
+// UnmarshalJSON 反序列化请求/响应头配置，保留未建模的字段
+func (h *HeadersConfig) UnmarshalJSON(data []byte) error {
+	return unmarshalExtra(data, (*headersConfigJSON)(h), &h.Extra)
+}
+
+// MarshalJSON 序列化上游请求改写
+func (r ProxyRewrite) MarshalJSON() ([]byte, error) {
+	return marshalExtra(proxyRewriteJSON(r), r.Extra)
+}
+
+// UnmarshalJSON 反序列化上游请求改写，保留未建模的字段 (如 strip_path_prefix)
+func (r *ProxyRewrite) UnmarshalJSON(data []byte) error {
+	return unmarshalExtra(data, (*proxyRewriteJSON)(r), &r.Extra)
+}
+
+// MarshalJSON 序列化响应处理器
+func (h ResponseHandler) MarshalJSON() ([]byte, error) {
+	return marshalExtra(responseHandlerJSON(h), h.Extra)
+}
+
+// UnmarshalJSON 反序列化响应处理器，保留未建模的字段
+func (h *ResponseHandler) UnmarshalJSON(data []byte) error {
+	return unmarshalExtra(data, (*responseHandlerJSON)(h), &h.Extra)
+}
+
+// MarshalJSON 序列化子串替换规则
+func (r SubstringReplacer) MarshalJSON() ([]byte, error) {
+	return marshalExtra(substringReplacerJSON(r), r.Extra)
+}
+
+// UnmarshalJSON 反序列化子串替换规则，保留未建模的字段
+func (r *SubstringReplacer) UnmarshalJSON(data []byte) error {
+	return unmarshalExtra(data, (*substringReplacerJSON)(r), &r.Extra)
+}
+
+// MarshalJSON 序列化正则替换规则
+func (r RegexReplacer) MarshalJSON() ([]byte, error) {
+	return marshalExtra(regexReplacerJSON(r), r.Extra)
+}
+
+// UnmarshalJSON 反序列化正则替换规则，保留未建模的字段
+func (r *RegexReplacer) UnmarshalJSON(data []byte) error {
+	return unmarshalExtra(data, (*regexReplacerJSON)(r), &r.Extra)
+}
+
+// MarshalJSON 序列化编码格式配置
+func (e Encoding) MarshalJSON() ([]byte, error) {
+	return marshalExtra(encodingJSON(e), e.Extra)
+}
+
+// UnmarshalJSON 反序列化编码格式配置，保留未建模的字段
+func (e *Encoding) UnmarshalJSON(data []byte) error {
+	return unmarshalExtra(data, (*encodingJSON)(e), &e.Extra)
+}
+
+// MarshalJSON 序列化响应匹配规则
+func (m ResponseMatch) MarshalJSON() ([]byte, error) {
+	return marshalExtra(responseMatchJSON(m), m.Extra)
+}
+
+// UnmarshalJSON 反序列化响应匹配规则，保留未建模的字段
+func (m *ResponseMatch) UnmarshalJSON(data []byte) error {
+	return unmarshalExtra(data, (*responseMatchJSON)(m), &m.Extra)
+}
+
+// MarshalJSON 序列化认证提供者
+func (p AuthProviders) MarshalJSON() ([]byte, error) {
+	return marshalExtra(authProvidersJSON(p), p.Extra)
+}
+
+// UnmarshalJSON 反序列化认证提供者，保留未建模的字段
+func (p *AuthProviders) UnmarshalJSON(data []byte) error {
+	return unmarshalExtra(data, (*authProvidersJSON)(p), &p.Extra)
+}
+
+// MarshalJSON 序列化HTTP 基本认证配置
+func (a HTTPBasicAuth) MarshalJSON() ([]byte, error) {
+	return marshalExtra(httpBasicAuthJSON(a), a.Extra)
+}
+
+// UnmarshalJSON 反序列化HTTP 基本认证配置，保留未建模的字段 (如 hash_cache)
+func (a *HTTPBasicAuth) UnmarshalJSON(data []byte) error {
+	return unmarshalExtra(data, (*httpBasicAuthJSON)(a), &a.Extra)
+}
+
+// MarshalJSON 序列化基本认证账户
+func (a BasicAuthAccount) MarshalJSON() ([]byte, error) {
+	return marshalExtra(basicAuthAccountJSON(a), a.Extra)
+}
+
+// UnmarshalJSON 反序列化基本认证账户，保留未建模的字段
+func (a *BasicAuthAccount) UnmarshalJSON(data []byte) error {
+	return unmarshalExtra(data, (*basicAuthAccountJSON)(a), &a.Extra)
+}
+
+// MarshalJSON 序列化密码哈希算法
+func (h BasicAuthHash) MarshalJSON() ([]byte, error) {
+	return marshalExtra(basicAuthHashJSON(h), h.Extra)
+}
+
+// UnmarshalJSON 反序列化密码哈希算法，保留未建模的字段
+func (h *BasicAuthHash) UnmarshalJSON(data []byte) error {
+	return unmarshalExtra(data, (*basicAuthHashJSON)(h), &h.Extra)
+}
+
+// MarshalJSON 序列化头部操作
+func (o HeaderOps) MarshalJSON() ([]byte, error) {
+	return marshalExtra(headerOpsJSON(o), o.Extra)
+}
+
+// UnmarshalJSON 反序列化头部操作，保留未建模的字段 (如响应头操作的 require)
+func (o *HeaderOps) UnmarshalJSON(data []byte) error {
+	return unmarshalExtra(data, (*headerOpsJSON)(o), &o.Extra)
+}
+
+// MarshalJSON 序列化头部替换规则
+func (r Replacement) MarshalJSON() ([]byte, error) {
+	return marshalExtra(replacementJSON(r), r.Extra)
+}
+
+// UnmarshalJSON 反序列化头部替换规则，保留未建模的字段
+func (r *Replacement) UnmarshalJSON(data []byte) error {
+	return unmarshalExtra(data, (*replacementJSON)(r), &r.Extra)
+}
+
+// MarshalJSON 序列化上游 TLS 配置
+func (t TransportTLS) MarshalJSON() ([]byte, error) {
+	return marshalExtra(transportTLSJSON(t), t.Extra)
+}
+
+// UnmarshalJSON 反序列化上游 TLS 配置，保留未建模的字段 (如 client_certificate_file)
+func (t *TransportTLS) UnmarshalJSON(data []byte) error {
+	return unmarshalExtra(data, (*transportTLSJSON)(t), &t.Extra)
+}
+
+// MarshalJSON 序列化CA 证书池
+func (p CAPool) MarshalJSON() ([]byte, error) {
+	return marshalExtra(caPoolJSON(p), p.Extra)
+}
+
+// UnmarshalJSON 反序列化CA 证书池，保留未建模的字段
+func (p *CAPool) UnmarshalJSON(data []byte) error {
+	return unmarshalExtra(data, (*caPoolJSON)(p), &p.Extra)
+}
+
+// MarshalJSON 序列化长连接配置
+func (k KeepAlive) MarshalJSON() ([]byte, error) {
+	return marshalExtra(keepAliveJSON(k), k.Extra)
+}
+
+// UnmarshalJSON 反序列化长连接配置，保留未建模的字段
+func (k *KeepAlive) UnmarshalJSON(data []byte) error {
+	return unmarshalExtra(data, (*keepAliveJSON)(k), &k.Extra)
+}
+
+// MarshalJSON 序列化限流区域
+func (z RateLimitZone) MarshalJSON() ([]byte, error) {
+	return marshalExtra(rateLimitZoneJSON(z), z.Extra)
+}
+
+// UnmarshalJSON 反序列化限流区域，保留未建模的字段
+func (z *RateLimitZone) UnmarshalJSON(data []byte) error {
+	return unmarshalExtra(data, (*rateLimitZoneJSON)(z), &z.Extra)
+}
+
+// MarshalJSON 序列化目录浏览配置
+func (b FileBrowse) MarshalJSON() ([]byte, error) {
+	return marshalExtra(fileBrowseJSON(b), b.Extra)
+}
+
+// UnmarshalJSON 反序列化目录浏览配置，保留未建模的字段
+func (b *FileBrowse) UnmarshalJSON(data []byte) error {
+	return unmarshalExtra(data, (*fileBrowseJSON)(b), &b.Extra)
+}
+
+// MarshalJSON 序列化负载均衡配置
+func (lb LoadBalancing) MarshalJSON() ([]byte, error) {
+	return marshalExtra(loadBalancingJSON(lb), lb.Extra)
+}
+
+// UnmarshalJSON 反序列化负载均衡配置，保留未建模的字段 (如 retry_match)
+func (lb *LoadBalancing) UnmarshalJSON(data []byte) error {
+	return unmarshalExtra(data, (*loadBalancingJSON)(lb), &lb.Extra)
+}
+
+// MarshalJSON 序列化上游选择策略
+func (p SelectionPolicy) MarshalJSON() ([]byte, error) {
+	return marshalExtra(selectionPolicyJSON(p), p.Extra)
+}
+
+// UnmarshalJSON 反序列化上游选择策略，保留未建模的字段
+func (p *SelectionPolicy) UnmarshalJSON(data []byte) error {
+	return unmarshalExtra(data, (*selectionPolicyJSON)(p), &p.Extra)
+}
+
+// MarshalJSON 序列化服务器访问日志配置
+func (l ServerLogs) MarshalJSON() ([]byte, error) {
+	return marshalExtra(serverLogsJSON(l), l.Extra)
+}
+
+// UnmarshalJSON 反序列化服务器访问日志配置，保留未建模的字段 (如 should_log_credentials、trace)
+func (l *ServerLogs) UnmarshalJSON(data []byte) error {
+	return unmarshalExtra(data, (*serverLogsJSON)(l), &l.Extra)
+}
+
+// MarshalJSON 序列化HTTP 错误处理配置
+func (e HTTPErrorConfig) MarshalJSON() ([]byte, error) {
+	return marshalExtra(httpErrorConfigJSON(e), e.Extra)
+}
+
+// UnmarshalJSON 反序列化HTTP 错误处理配置，保留未建模的字段
+func (e *HTTPErrorConfig) UnmarshalJSON(data []byte) error {
+	return unmarshalExtra(data, (*httpErrorConfigJSON)(e), &e.Extra)
+}
+
+// MarshalJSON 序列化监听器包装器
+func (w ListenerWrapper) MarshalJSON() ([]byte, error) {
+	return marshalExtra(listenerWrapperJSON(w), w.Extra)
+}
+
+// UnmarshalJSON 反序列化监听器包装器，保留未建模的字段
+func (w *ListenerWrapper) UnmarshalJSON(data []byte) error {
+	return unmarshalExtra(data, (*listenerWrapperJSON)(w), &w.Extra)
+}
+
+// MarshalJSON 序列化受信任代理
+func (p TrustedProxies) MarshalJSON() ([]byte, error) {
+	return marshalExtra(trustedProxiesJSON(p), p.Extra)
+}
+
+// UnmarshalJSON 反序列化受信任代理，保留未建模的字段
+func (p *TrustedProxies) UnmarshalJSON(data []byte) error {
+	return unmarshalExtra(data, (*trustedProxiesJSON)(p), &p.Extra)
+}
+
+// MarshalJSON 序列化响应头操作 - 嵌入的 HeaderOps 带有自定义方法，需要单独写入 deferred
+func (o RespHeaderOps) MarshalJSON() ([]byte, error) {
+	ops := o.HeaderOps
+	if o.Deferred {
+		ops.Extra = make(Extra, len(o.Extra)+1)
+		for key, raw := range o.Extra {
+			ops.Extra[key] = raw
+		}
+		ops.Extra["deferred"] = json.RawMessage("true")
+	}
+	return json.Marshal(ops)
+}
+
+// UnmarshalJSON 反序列化响应头操作，deferred 从 HeaderOps 的未建模字段中取出
+func (o *RespHeaderOps) UnmarshalJSON(data []byte) error {
+	var ops HeaderOps
+	if err := json.Unmarshal(data, &ops); err != nil {
+		return err
+	}
+	deferred := false
+	if raw, ok := ops.Extra["deferred"]; ok {
+		if err := json.Unmarshal(raw, &deferred); err != nil {
+			return err
+		}
+		delete(ops.Extra, "deferred")
+		if len(ops.Extra) == 0 {
+			ops.Extra = nil
+		}
+	}
+	o.HeaderOps = ops
+	o.Deferred = deferred
+	return nil
+}
@@ -0,0 +1,320 @@
+package types
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// JSON Patch 操作类型 (RFC 6902)
+const (
+	PatchAdd     = "add"
+	PatchRemove  = "remove"
+	PatchReplace = "replace"
+	PatchMove    = "move"
+	PatchCopy    = "copy"
+	PatchTest    = "test"
+)
+
+// JSON Patch 操作 - 路径为 JSON Pointer (RFC 6901)，如 "/apps/http/servers/srv0/listen/-"
+type PatchOperation struct {
+	Op    string      `json:"op"`             // 操作类型 (add、remove、replace、move、copy、test)
+	Path  string      `json:"path"`           // 目标位置
+	From  string      `json:"from,omitempty"` // 来源位置 (move、copy)
+	Value interface{} `json:"value"`          // 操作的值 (add、replace、test)
+}
+
+// String 格式化操作 (如 "add /apps/http/servers/srv0/listen/-")
+func (o PatchOperation) String() string {
+	if o.From != "" {
+		return fmt.Sprintf("%s %s -> %s", o.Op, o.From, o.Path)
+	}
+	return fmt.Sprintf("%s %s", o.Op, o.Path)
+}
+
+// JSON Patch 文档 - 按顺序执行的操作列表
+type JSONPatch []PatchOperation
+
+// DecodePatch 解析 JSON Patch 文档
+func DecodePatch(data []byte) (JSONPatch, error) {
+	var patch JSONPatch
+	if err := json.Unmarshal(data, &patch); err != nil {
+		return nil, fmt.Errorf("解析 JSON Patch 失败: %w", err)
+	}
+	for i, op := range patch {
+		switch op.Op {
+		case PatchAdd, PatchRemove, PatchReplace, PatchMove, PatchCopy, PatchTest:
+		default:
+			return nil, fmt.Errorf("第 %d 个操作: 未知的操作类型 %q", i+1, op.Op)
+		}
+	}
+	return patch, nil
+}
+
+// Apply 在 doc 的副本上按顺序执行所有操作，返回修改后的文档，doc 本身不变
+// 任一操作失败 (路径不存在、test 不相等等) 时返回错误
+func (p JSONPatch) Apply(doc interface{}) (interface{}, error) {
+	result, err := normalize(doc)
+	if err != nil {
+		return nil, err
+	}
+	for i, op := range p {
+		if result, err = op.Apply(result); err != nil {
+			return nil, fmt.Errorf("第 %d 个操作 (%s) 失败: %w", i+1, op, err)
+		}
+	}
+	return result, nil
+}
+
+// Apply 执行单个操作，doc 必须是 JSON 解码得到的值 (map[string]interface{}、[]interface{} 等)
+// 返回修改后的文档，doc 可能被原地修改
+func (o PatchOperation) Apply(doc interface{}) (interface{}, error) {
+	path, err := ParsePointer(o.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	switch o.Op {
+	case PatchAdd, PatchReplace, PatchTest:
+		value, err := normalize(o.Value)
+		if err != nil {
+			return nil, err
+		}
+		switch o.Op {
+		case PatchAdd:
+			return addValue(doc, path, value)
+		case PatchReplace:
+			return replaceValue(doc, path, value)
+		}
+		current, err := GetPointer(doc, path)
+		if err != nil {
+			return nil, err
+		}
+		if !reflect.DeepEqual(current, value) {
+			return nil, fmt.Errorf("测试失败: %s 的值与期望不符", o.Path)
+		}
+		return doc, nil
+
+	case PatchRemove:
+		if len(path) == 0 {
+			return nil, fmt.Errorf("不能删除整个文档")
+		}
+		return removeValue(doc, path)
+
+	case PatchMove, PatchCopy:
+		from, err := ParsePointer(o.From)
+		if err != nil {
+			return nil, err
+		}
+		value, err := GetPointer(doc, from)
+		if err != nil {
+			return nil, err
+		}
+		if o.Op == PatchCopy {
+			if value, err = normalize(value); err != nil {
+				return nil, err
+			}
+			return addValue(doc, path, value)
+		}
+		if o.From == o.Path {
+			return doc, nil
+		}
+		if strings.HasPrefix(o.Path, o.From+"/") {
+			return nil, fmt.Errorf("不能将 %s 移动到其子路径 %s", o.From, o.Path)
+		}
+		if doc, err = removeValue(doc, from); err != nil {
+			return nil, err
+		}
+		return addValue(doc, path, value)
+	}
+
+	return nil, fmt.Errorf("未知的操作类型: %q", o.Op)
+}
+
+// ParsePointer 解析 JSON Pointer 为各级键 ("" 表示整个文档，"~1" 和 "~0" 分别转义 "/" 和 "~")
+func ParsePointer(pointer string) ([]string, error) {
+	if pointer == "" {
+		return nil, nil
+	}
+	if !strings.HasPrefix(pointer, "/") {
+		return nil, fmt.Errorf("无效的 JSON Pointer: %q (必须以 '/' 开头)", pointer)
+	}
+	tokens := strings.Split(pointer[1:], "/")
+	for i, token := range tokens {
+		tokens[i] = strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+	}
+	return tokens, nil
+}
+
+// GetPointer 获取 JSON Pointer 指向的值
+func GetPointer(doc interface{}, path []string) (interface{}, error) {
+	node := doc
+	for i, token := range path {
+		switch val := node.(type) {
+		case map[string]interface{}:
+			child, ok := val[token]
+			if !ok {
+				return nil, fmt.Errorf("路径不存在: %s", pointerString(path[:i+1]))
+			}
+			node = child
+		case []interface{}:
+			index, err := arrayIndex(token, len(val)-1)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", pointerString(path[:i+1]), err)
+			}
+			node = val[index]
+		default:
+			return nil, fmt.Errorf("路径不存在: %s", pointerString(path[:i+1]))
+		}
+	}
+	return node, nil
+}
+
+// pointerString 将各级键格式化为 JSON Pointer
+func pointerString(path []string) string {
+	var b strings.Builder
+	for _, token := range path {
+		b.WriteString("/")
+		b.WriteString(strings.ReplaceAll(strings.ReplaceAll(token, "~", "~0"), "/", "~1"))
+	}
+	return b.String()
+}
+
+// arrayIndex 解析数组下标，max 为允许的最大下标 (不允许前导零和负数)
+func arrayIndex(token string, max int) (int, error) {
+	if token == "" || (len(token) > 1 && token[0] == '0') {
+		return 0, fmt.Errorf("无效的数组下标: %q", token)
+	}
+	index, err := strconv.Atoi(token)
+	if err != nil || index < 0 {
+		return 0, fmt.Errorf("无效的数组下标: %q", token)
+	}
+	if index > max {
+		return 0, fmt.Errorf("数组下标越界: %d", index)
+	}
+	return index, nil
+}
+
+// update 定位最后一级键所在的容器并调用 fn 修改，返回修改后的文档
+func update(doc interface{}, path []string, fn func(container interface{}, key string) (interface{}, error)) (interface{}, error) {
+	return updateAt(doc, path, 0, fn)
+}
+
+// updateAt 从第 i 级键开始递归定位容器，修改后依次写回各级父容器
+func updateAt(doc interface{}, path []string, i int, fn func(container interface{}, key string) (interface{}, error)) (interface{}, error) {
+	if i == len(path)-1 {
+		return fn(doc, path[i])
+	}
+
+	switch val := doc.(type) {
+	case map[string]interface{}:
+		child, ok := val[path[i]]
+		if !ok {
+			return nil, fmt.Errorf("路径不存在: %s", pointerString(path[:i+1]))
+		}
+		updated, err := updateAt(child, path, i+1, fn)
+		if err != nil {
+			return nil, err
+		}
+		val[path[i]] = updated
+		return val, nil
+	case []interface{}:
+		index, err := arrayIndex(path[i], len(val)-1)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", pointerString(path[:i+1]), err)
+		}
+		updated, err := updateAt(val[index], path, i+1, fn)
+		if err != nil {
+			return nil, err
+		}
+		val[index] = updated
+		return val, nil
+	}
+	return nil, fmt.Errorf("路径不存在: %s", pointerString(path[:i+1]))
+}
+
+// addValue 添加值 - 对象中设置或替换成员，数组中在下标处插入 ("-" 表示追加到末尾)
+func addValue(doc interface{}, path []string, value interface{}) (interface{}, error) {
+	if len(path) == 0 {
+		return value, nil
+	}
+	return update(doc, path, func(container interface{}, key string) (interface{}, error) {
+		switch val := container.(type) {
+		case map[string]interface{}:
+			val[key] = value
+			return val, nil
+		case []interface{}:
+			if key == "-" {
+				return append(val, value), nil
+			}
+			index, err := arrayIndex(key, len(val))
+			if err != nil {
+				return nil, err
+			}
+			return append(val[:index], append([]interface{}{value}, val[index:]...)...), nil
+		}
+		return nil, fmt.Errorf("父路径不是对象或数组: %s", pointerString(path[:len(path)-1]))
+	})
+}
+
+// removeValue 删除已存在的值
+func removeValue(doc interface{}, path []string) (interface{}, error) {
+	return update(doc, path, func(container interface{}, key string) (interface{}, error) {
+		switch val := container.(type) {
+		case map[string]interface{}:
+			if _, ok := val[key]; !ok {
+				return nil, fmt.Errorf("路径不存在: %s", pointerString(path))
+			}
+			delete(val, key)
+			return val, nil
+		case []interface{}:
+			index, err := arrayIndex(key, len(val)-1)
+			if err != nil {
+				return nil, err
+			}
+			return append(val[:index], val[index+1:]...), nil
+		}
+		return nil, fmt.Errorf("路径不存在: %s", pointerString(path))
+	})
+}
+
+// replaceValue 替换已存在的值
+func replaceValue(doc interface{}, path []string, value interface{}) (interface{}, error) {
+	if len(path) == 0 {
+		return value, nil
+	}
+	return update(doc, path, func(container interface{}, key string) (interface{}, error) {
+		switch val := container.(type) {
+		case map[string]interface{}:
+			if _, ok := val[key]; !ok {
+				return nil, fmt.Errorf("路径不存在: %s", pointerString(path))
+			}
+			val[key] = value
+			return val, nil
+		case []interface{}:
+			index, err := arrayIndex(key, len(val)-1)
+			if err != nil {
+				return nil, err
+			}
+			val[index] = value
+			return val, nil
+		}
+		return nil, fmt.Errorf("路径不存在: %s", pointerString(path))
+	})
+}
+
+// normalize 通过 JSON 序列化将值转换为 JSON 解码的表示 (同时复制一份)，
+// 使类型化结构、整数等与解码得到的配置可以直接比较和修改
+func normalize(v interface{}) (interface{}, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, fmt.Errorf("序列化值失败: %w", err)
+	}
+	var value interface{}
+	if err := json.Unmarshal(data, &value); err != nil {
+		return nil, fmt.Errorf("解析值失败: %w", err)
+	}
+	return value, nil
+}
@@ -1,22 +1,59 @@
 package types
 
+import (
+	"encoding/json"
+	"reflect"
+	"strconv"
+)
+
 // Caddy 配置结构 - 表示整个 Caddy 配置的顶层结构
+// 读取、修改后写回时保留未建模的字段 (如 admin、storage)
 type CaddyConfig struct {
-	Apps map[string]interface{} `json:"apps"`
+	Logging *LoggingConfig `json:"logging,omitempty"` // 日志配置
+	Apps    *Apps          `json:"apps,omitempty"`    // 应用配置
+	Extra   Extra          `json:"-"`                 // 未建模的顶层字段
 }
 
 // 路由规则结构 - 定义单个路由规则
 type Route struct {
-	ID       string        `json:"@id,omitempty"`       // 路由唯一标识符
-	Match    []RouteMatch  `json:"match"`               // 匹配条件列表
-	Handle   []Handler     `json:"handle"`              // 处理器列表
-	Terminal bool          `json:"terminal"`            // 是否为终端路由
+	ID       string       `json:"@id,omitempty"`      // 路由唯一标识符
+	Match    []RouteMatch `json:"match,omitempty"`    // 匹配条件列表
+	Handle   []Handler    `json:"handle,omitempty"`   // 处理器列表
+	Terminal bool         `json:"terminal,omitempty"` // 是否为终端路由
+	Extra    Extra        `json:"-"`                  // 未建模的字段 (如 group)
 }
 
 // 路由匹配规则 - 定义路由匹配条件
 type RouteMatch struct {
-	Host []string `json:"host,omitempty"` // 主机名匹配列表
-	Path []string `json:"path,omitempty"` // 路径匹配列表
+	Host []string   `json:"host,omitempty"` // 主机名匹配列表
+	Path []string   `json:"path,omitempty"` // 路径匹配列表
+	File *FileMatch `json:"file,omitempty"` // 文件存在性匹配 (用于 try_files)
+
+	Method []string            `json:"method,omitempty"` // 请求方法匹配列表 (如 "OPTIONS")
+	Header map[string][]string `json:"header,omitempty"` // 请求头匹配 (值支持 * 通配)
+
+	RemoteIP   *IPMatch     `json:"remote_ip,omitempty"`  // 直连客户端 IP 匹配
+	ClientIP   *IPMatch     `json:"client_ip,omitempty"`  // 客户端 IP 匹配 (配置受信任代理时为真实客户端地址)
+	Not        []RouteMatch `json:"not,omitempty"`        // 取反匹配 (任一匹配集命中时不匹配)
+	Expression string       `json:"expression,omitempty"` // CEL 表达式匹配 (如 "{http.error.status_code} in [404]")
+
+	ID string `json:"@id,omitempty"` // 匹配集唯一标识符 (用于直接更新匹配条件)
+
+	Extra Extra `json:"-"` // 未建模的匹配器 (如 query、protocol)
+}
+
+// IP 匹配规则 - remote_ip / client_ip 匹配器
+type IPMatch struct {
+	Ranges []string `json:"ranges"` // IP 或 CIDR 范围列表
+	Extra  Extra    `json:"-"`      // 未建模的字段
+}
+
+// 文件匹配规则 - 按顺序尝试文件，匹配第一个存在的文件
+type FileMatch struct {
+	Root      string   `json:"root,omitempty"`       // 站点根目录
+	TryFiles  []string `json:"try_files,omitempty"`  // 依次尝试的文件路径 (支持占位符)
+	SplitPath []string `json:"split_path,omitempty"` // 按这些子串拆分路径 (如 ".php"，用于 PATH_INFO)
+	Extra     Extra    `json:"-"`                    // 未建模的字段 (如 try_policy)
 }
 
 // 处理器结构 - 定义路由处理逻辑
@@ -24,38 +61,457 @@ type Handler struct {
 	Handler   string     `json:"handler"`              // 处理器类型 (如 "reverse_proxy", "subroute")
 	Upstreams []Upstream `json:"upstreams,omitempty"`  // 上游服务器列表 (用于反向代理)
 	Routes    []Route    `json:"routes,omitempty"`     // 子路由列表 (用于子路由处理器)
+	ID        string     `json:"@id,omitempty"`        // 处理器唯一标识符 (用于直接更新或删除单个处理器)
+
+	// file_server 处理器字段
+	Root               string              `json:"root,omitempty"`                // 站点根目录
+	Hide               []string            `json:"hide,omitempty"`                // 隐藏的文件或目录 (支持通配符)
+	IndexNames         []string            `json:"index_names,omitempty"`         // 索引文件名列表
+	Browse             *FileBrowse         `json:"browse,omitempty"`              // 目录浏览配置 (非 nil 即启用)
+	Precompressed      map[string]struct{} `json:"precompressed,omitempty"`       // 预压缩文件格式 (如 "br", "gzip", "zstd")
+	PrecompressedOrder []string            `json:"precompressed_order,omitempty"` // 预压缩格式优先顺序
+
+	// static_response 处理器字段
+	StatusCode WeakString          `json:"status_code,omitempty"` // 响应状态码 (支持占位符)
+	Body       string              `json:"body,omitempty"`        // 响应内容
+	Headers    map[string][]string `json:"headers,omitempty"`     // 响应头 (如 Location)
+
+	// headers 处理器字段
+	Request  *HeaderOps     `json:"request,omitempty"`  // 请求头操作
+	Response *RespHeaderOps `json:"response,omitempty"` // 响应头操作
+
+	// reverse_proxy 处理器的请求/响应头操作，序列化为 "headers" 键
+	// (与 static_response 的 Headers 共用同一个 JSON 键，见 MarshalJSON)
+	UpstreamHeaders *HeadersConfig `json:"-"`
+
+	// reverse_proxy 处理器扩展字段
+	Rewrite        *ProxyRewrite     `json:"rewrite,omitempty"`         // 发送到上游前改写请求方法和 URI (用于 forward auth)
+	HandleResponse []ResponseHandler `json:"handle_response,omitempty"` // 根据上游响应执行的路由
+	LoadBalancing  *LoadBalancing    `json:"load_balancing,omitempty"`  // 负载均衡配置
+	Transport      *Transport        `json:"transport,omitempty"`       // 与上游通信的传输配置
+
+	// reverse_proxy 流式传输选项 (WebSocket, SSE 等)
+	FlushInterval    Duration `json:"flush_interval,omitempty"`     // 响应刷新间隔 (-1 表示立即刷新)
+	StreamTimeout    Duration `json:"stream_timeout,omitempty"`     // 长连接流 (如 WebSocket) 的最长持续时间
+	StreamCloseDelay Duration `json:"stream_close_delay,omitempty"` // 配置重载时延迟关闭长连接流的时间
+	RequestBuffers   int64    `json:"request_buffers,omitempty"`    // 请求体缓冲大小 (字节，-1 表示不限制)
+	ResponseBuffers  int64    `json:"response_buffers,omitempty"`   // 响应体缓冲大小 (字节，-1 表示不限制)
+
+	// encode 处理器字段
+	Encodings     map[string]Encoding `json:"encodings,omitempty"`      // 启用的编码格式 (如 "gzip", "zstd")
+	Prefer        []string            `json:"prefer,omitempty"`         // 客户端未指定偏好时的编码优先顺序
+	MinimumLength int                 `json:"minimum_length,omitempty"` // 启用压缩的最小响应长度 (字节)
+	Match         *ResponseMatch      `json:"match,omitempty"`          // 仅压缩匹配的响应 (如按 Content-Type)
+
+	// authentication 处理器字段
+	Providers *AuthProviders `json:"providers,omitempty"` // 认证提供者
+
+	// request_body 处理器字段
+	MaxSize int64 `json:"max_size,omitempty"` // 请求体最大大小 (字节)
+
+	// rate_limit 处理器字段 (需要包含 caddy-ratelimit 模块的 Caddy)
+	RateLimits map[string]*RateLimitZone `json:"rate_limits,omitempty"` // 限流区域
+
+	// rewrite 处理器字段
+	URI             string              `json:"uri,omitempty"`               // 重写后的 URI (支持占位符)
+	Method          string              `json:"method,omitempty"`            // 重写后的请求方法
+	StripPathPrefix string              `json:"strip_path_prefix,omitempty"` // 去除的路径前缀
+	StripPathSuffix string              `json:"strip_path_suffix,omitempty"` // 去除的路径后缀
+	URISubstring    []SubstringReplacer `json:"uri_substring,omitempty"`     // URI 子串替换
+	PathRegexp      []RegexReplacer     `json:"path_regexp,omitempty"`       // 路径正则替换
+
+	Extra Extra `json:"-"` // 未建模的处理器字段 (如 reverse_proxy 的 health_checks)
+}
+
+// handlerJSON 与 Handler 字段相同但不带自定义序列化方法，避免递归调用
+type handlerJSON Handler
+
+// MarshalJSON 序列化处理器
+// reverse_proxy 的 "headers" 为请求/响应头操作，static_response 的 "headers" 为响应头映射
+func (h Handler) MarshalJSON() ([]byte, error) {
+	aux := struct {
+		handlerJSON
+		Headers interface{} `json:"headers,omitempty"`
+	}{handlerJSON: handlerJSON(h)}
+
+	if h.Handler == "reverse_proxy" {
+		if h.UpstreamHeaders != nil {
+			aux.Headers = h.UpstreamHeaders
+		}
+	} else if len(h.Headers) > 0 {
+		aux.Headers = h.Headers
+	}
+
+	return marshalExtra(aux, h.Extra)
+}
+
+// UnmarshalJSON 反序列化处理器，根据处理器类型解析 "headers" 键
+func (h *Handler) UnmarshalJSON(data []byte) error {
+	aux := struct {
+		*handlerJSON
+		Headers json.RawMessage `json:"headers,omitempty"`
+	}{handlerJSON: (*handlerJSON)(h)}
+
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+	extra, err := unknownFields(data, reflect.TypeOf(handlerJSON{}))
+	if err != nil {
+		return err
+	}
+	h.Extra = extra
+	if len(aux.Headers) == 0 || string(aux.Headers) == "null" {
+		return nil
+	}
+
+	if h.Handler == "reverse_proxy" {
+		return json.Unmarshal(aux.Headers, &h.UpstreamHeaders)
+	}
+	return json.Unmarshal(aux.Headers, &h.Headers)
+}
+
+// 请求/响应头配置 - reverse_proxy 处理器的 headers 选项
+type HeadersConfig struct {
+	Request  *HeaderOps     `json:"request,omitempty"`  // 发送到上游的请求头操作
+	Response *RespHeaderOps `json:"response,omitempty"` // 返回给客户端的响应头操作
+	Extra    Extra          `json:"-"`                  // 未建模的字段
+}
+
+// 上游请求改写 - reverse_proxy 的 rewrite 选项
+type ProxyRewrite struct {
+	Method string `json:"method,omitempty"` // 改写后的请求方法
+	URI    string `json:"uri,omitempty"`    // 改写后的 URI (支持占位符)
+	Extra  Extra  `json:"-"`                // 未建模的字段 (如 strip_path_prefix)
+}
+
+// 响应处理器 - 当上游响应匹配时执行的路由
+type ResponseHandler struct {
+	Match      *ResponseMatch `json:"match,omitempty"`       // 响应匹配条件
+	StatusCode WeakString     `json:"status_code,omitempty"` // 覆盖响应状态码
+	Routes     []Route        `json:"routes,omitempty"`      // 匹配时执行的路由
+	Extra      Extra          `json:"-"`                     // 未建模的字段
+}
+
+// 子串替换规则 - rewrite 处理器的 uri_substring 选项
+type SubstringReplacer struct {
+	Find    string `json:"find"`            // 要查找的子串
+	Replace string `json:"replace"`         // 替换内容
+	Limit   int    `json:"limit,omitempty"` // 最大替换次数 (0 表示不限制)
+	Extra   Extra  `json:"-"`               // 未建模的字段
+}
+
+// 正则替换规则 - rewrite 处理器的 path_regexp 选项
+type RegexReplacer struct {
+	Find    string `json:"find"`    // 正则表达式
+	Replace string `json:"replace"` // 替换内容 (支持 $1 等分组引用)
+	Extra   Extra  `json:"-"`       // 未建模的字段
+}
+
+// 编码格式配置 - encode 处理器中单个编码的选项
+type Encoding struct {
+	Level int   `json:"level,omitempty"` // 压缩级别 (0 表示使用默认值)
+	Extra Extra `json:"-"`               // 未建模的字段
+}
+
+// 响应匹配规则 - 按状态码或响应头匹配
+type ResponseMatch struct {
+	StatusCode []int               `json:"status_code,omitempty"` // 状态码列表 (如 2 表示所有 2xx)
+	Headers    map[string][]string `json:"headers,omitempty"`     // 响应头匹配
+	Extra      Extra               `json:"-"`                     // 未建模的字段
+}
+
+// 认证提供者 - authentication 处理器支持的认证方式
+type AuthProviders struct {
+	HTTPBasic *HTTPBasicAuth `json:"http_basic,omitempty"` // HTTP 基本认证
+	Extra     Extra          `json:"-"`                    // 未建模的认证方式
+}
+
+// HTTP 基本认证配置
+type HTTPBasicAuth struct {
+	Accounts []BasicAuthAccount `json:"accounts"`        // 账户列表
+	Hash     *BasicAuthHash     `json:"hash,omitempty"`  // 密码哈希算法
+	Realm    string             `json:"realm,omitempty"` // 认证域
+	Extra    Extra              `json:"-"`               // 未建模的字段 (如 hash_cache)
+}
+
+// 基本认证账户
+type BasicAuthAccount struct {
+	Username string `json:"username"` // 用户名
+	Password string `json:"password"` // 哈希后的密码
+	Extra    Extra  `json:"-"`        // 未建模的字段 (如 salt)
+}
+
+// 基本认证密码哈希算法
+type BasicAuthHash struct {
+	Algorithm string `json:"algorithm"` // 算法名称 (如 "bcrypt")
+	Extra     Extra  `json:"-"`         // 未建模的字段
+}
+
+// 头部操作 - 定义添加、设置、删除和替换头部字段
+type HeaderOps struct {
+	Add     map[string][]string      `json:"add,omitempty"`     // 添加头部字段 (保留已有值)
+	Set     map[string][]string      `json:"set,omitempty"`     // 设置头部字段 (覆盖已有值)
+	Delete  []string                 `json:"delete,omitempty"`  // 删除头部字段 (支持 * 通配)
+	Replace map[string][]Replacement `json:"replace,omitempty"` // 替换头部字段中的子串
+	Extra   Extra                    `json:"-"`                 // 未建模的字段 (如响应头操作的 require)
+}
+
+// 响应头操作 - 在 HeaderOps 基础上支持延迟执行
+type RespHeaderOps struct {
+	HeaderOps
+	Deferred bool `json:"deferred,omitempty"` // 是否在响应写出前才执行 (删除 Server 等头部时需要)
+}
+
+// 头部替换规则
+type Replacement struct {
+	Search       string `json:"search,omitempty"`        // 要查找的子串
+	SearchRegexp string `json:"search_regexp,omitempty"` // 要查找的正则表达式
+	Replace      string `json:"replace,omitempty"`       // 替换内容
+	Extra        Extra  `json:"-"`                       // 未建模的字段
+}
+
+// WeakString 弱类型字符串 - 兼容 Caddy 中既可为数字也可为字符串的字段 (如 status_code)
+type WeakString string
+
+// UnmarshalJSON 支持从 JSON 数字或字符串解析
+func (w *WeakString) UnmarshalJSON(data []byte) error {
+	if len(data) > 0 && data[0] == '"' {
+		var s string
+		if err := json.Unmarshal(data, &s); err != nil {
+			return err
+		}
+		*w = WeakString(s)
+		return nil
+	}
+	if string(data) == "null" {
+		return nil
+	}
+	*w = WeakString(string(data))
+	return nil
+}
+
+// Duration 时长 - 兼容 Caddy 中既可为纳秒整数也可为时长字符串的字段
+// 纯整数 (如 "-1") 序列化为 JSON 数字，其余 (如 "30s") 序列化为字符串
+type Duration string
+
+// MarshalJSON 整数时长序列化为数字，其余序列化为字符串
+func (d Duration) MarshalJSON() ([]byte, error) {
+	if _, err := strconv.ParseInt(string(d), 10, 64); err == nil {
+		return []byte(d), nil
+	}
+	return json.Marshal(string(d))
+}
+
+// UnmarshalJSON 支持从 JSON 数字或字符串解析
+func (d *Duration) UnmarshalJSON(data []byte) error {
+	var w WeakString
+	if err := w.UnmarshalJSON(data); err != nil {
+		return err
+	}
+	*d = Duration(w)
+	return nil
+}
+
+// 传输配置 - reverse_proxy 与上游通信的方式
+type Transport struct {
+	Protocol              string        `json:"protocol"`                          // 传输协议 (如 "http")
+	TLS                   *TransportTLS `json:"tls,omitempty"`                     // 与上游的 TLS 配置 (非 nil 即使用 HTTPS)
+	Versions              []string      `json:"versions,omitempty"`                // HTTP 版本 (如 "1.1", "2", "h2c", "3")
+	DialTimeout           Duration      `json:"dial_timeout,omitempty"`            // 连接上游的超时时间
+	ResponseHeaderTimeout Duration      `json:"response_header_timeout,omitempty"` // 等待上游响应头的超时时间
+	KeepAlive             *KeepAlive    `json:"keep_alive,omitempty"`              // 长连接配置
+	ReadBufferSize        int           `json:"read_buffer_size,omitempty"`        // 读缓冲区大小 (字节)
+	WriteBufferSize       int           `json:"write_buffer_size,omitempty"`       // 写缓冲区大小 (字节)
+	Compression           *bool         `json:"compression,omitempty"`             // 是否向上游请求压缩响应 (默认启用)
+
+	// fastcgi 传输字段
+	Root      string            `json:"root,omitempty"`       // 脚本根目录 (默认为 {http.vars.root})
+	SplitPath []string          `json:"split_path,omitempty"` // 拆分 SCRIPT_NAME 和 PATH_INFO 的子串 (如 ".php")
+	Env       map[string]string `json:"env,omitempty"`        // 额外的 FastCGI 环境变量
+
+	Extra Extra `json:"-"` // 未建模的字段
+}
+
+// 上游 TLS 配置
+type TransportTLS struct {
+	ServerName         string  `json:"server_name,omitempty"`          // TLS 握手使用的 SNI
+	InsecureSkipVerify bool    `json:"insecure_skip_verify,omitempty"` // 跳过证书校验 (仅用于自签名的内部服务)
+	CA                 *CAPool `json:"ca,omitempty"`                   // 受信任的 CA 证书池
+	Extra              Extra   `json:"-"`                              // 未建模的字段 (如 client_certificate_file、renegotiation)
+}
+
+// CA 证书池 - 用于校验上游证书
+type CAPool struct {
+	Provider string   `json:"provider"`            // 证书来源 (如 "file", "inline")
+	PEMFiles []string `json:"pem_files,omitempty"` // PEM 证书文件路径 (provider 为 "file" 时)
+	Extra    Extra    `json:"-"`                   // 未建模的字段 (如 trusted_ca_certs)
+}
+
+// 长连接配置
+type KeepAlive struct {
+	Enabled             *bool    `json:"enabled,omitempty"`                 // 是否启用长连接 (默认启用)
+	ProbeInterval       Duration `json:"probe_interval,omitempty"`          // TCP keepalive 探测间隔
+	MaxIdleConns        int      `json:"max_idle_conns,omitempty"`          // 最大空闲连接数
+	MaxIdleConnsPerHost int      `json:"max_idle_conns_per_host,omitempty"` // 每个上游的最大空闲连接数
+	IdleConnTimeout     Duration `json:"idle_timeout,omitempty"`            // 空闲连接超时时间
+	Extra               Extra    `json:"-"`                                 // 未建模的字段
+}
+
+// 限流区域 - 每个不同的 Key 值在窗口内最多允许 MaxEvents 个请求
+type RateLimitZone struct {
+	Key       string   `json:"key"`        // 限流键 (支持占位符，如 "{http.request.remote.host}")
+	Window    Duration `json:"window"`     // 滑动窗口时长
+	MaxEvents int      `json:"max_events"` // 窗口内允许的最大请求数
+	Extra     Extra    `json:"-"`          // 未建模的字段
+}
+
+// 目录浏览配置 - file_server 的 browse 选项
+type FileBrowse struct {
+	TemplateFile string `json:"template_file,omitempty"` // 自定义目录列表模板
+	Extra        Extra  `json:"-"`                       // 未建模的字段 (如 reveal_symlinks)
 }
 
 // 上游服务器 - 定义反向代理的目标服务器
 type Upstream struct {
-	Dial string `json:"dial"` // 目标服务器地址 (如 "localhost:8080")
+	ID    string `json:"@id,omitempty"` // 上游唯一标识符 (用于标记金丝雀上游)
+	Dial  string `json:"dial"`          // 目标服务器地址 (如 "localhost:8080")
+	Extra Extra  `json:"-"`             // 未建模的字段 (如 max_requests)
+}
+
+// 负载均衡配置 - reverse_proxy 的 load_balancing 选项
+type LoadBalancing struct {
+	SelectionPolicy *SelectionPolicy `json:"selection_policy,omitempty"` // 上游选择策略
+	Retries         int              `json:"retries,omitempty"`          // 失败重试次数
+	TryDuration     Duration         `json:"try_duration,omitempty"`     // 重试的最长持续时间
+	TryInterval     Duration         `json:"try_interval,omitempty"`     // 重试间隔
+	Extra           Extra            `json:"-"`                          // 未建模的字段 (如 retry_match)
+}
+
+// 上游选择策略
+type SelectionPolicy struct {
+	ID      string `json:"@id,omitempty"`     // 策略唯一标识符 (金丝雀发布时记录原有的选择策略)
+	Policy  string `json:"policy"`            // 策略名称 (如 "round_robin", "weighted_round_robin")
+	Weights []int  `json:"weights,omitempty"` // 各上游的权重 (用于 weighted_round_robin)
+	Extra   Extra  `json:"-"`                 // 未建模的字段 (如 cookie 策略的 name、secret)
 }
 
 // HTTP 服务器配置 - 定义 HTTP 服务器的配置
 type HTTPServer struct {
-	Listen    []string `json:"listen"`              // 监听地址列表
-	Routes    []Route  `json:"routes"`              // 路由列表
-	Protocols []string `json:"protocols,omitempty"` // 支持的协议列表
+	Listen            []string          `json:"listen"`                        // 监听地址列表
+	Routes            []Route           `json:"routes"`                        // 路由列表
+	Protocols         []string          `json:"protocols,omitempty"`           // 支持的协议列表 (h1, h2, h2c, h3)
+	ListenerWrappers  []ListenerWrapper `json:"listener_wrappers,omitempty"`   // 监听器包装器列表 (如 proxy_protocol)
+	TrustedProxies    *TrustedProxies   `json:"trusted_proxies,omitempty"`     // 受信任的代理地址范围
+	ClientIPHeaders   []string          `json:"client_ip_headers,omitempty"`   // 读取客户端 IP 的请求头
+	ReadTimeout       Duration          `json:"read_timeout,omitempty"`        // 读取整个请求的超时时间 (如 "30s")
+	ReadHeaderTimeout Duration          `json:"read_header_timeout,omitempty"` // 读取请求头的超时时间
+	WriteTimeout      Duration          `json:"write_timeout,omitempty"`       // 写入响应的超时时间
+	IdleTimeout       Duration          `json:"idle_timeout,omitempty"`        // 空闲连接的超时时间
+	Errors            *HTTPErrorConfig  `json:"errors,omitempty"`              // 错误处理路由
+	Logs              *ServerLogs       `json:"logs,omitempty"`                // 访问日志配置 (非 nil 即启用访问日志)
+	Extra             Extra             `json:"-"`                             // 未建模的字段 (如 tls_connection_policies、automatic_https)
+}
+
+// 服务器访问日志配置 - 将主机映射到命名日志记录器
+type ServerLogs struct {
+	DefaultLoggerName string              `json:"default_logger_name,omitempty"` // 未映射主机使用的日志记录器
+	LoggerNames       map[string][]string `json:"logger_names,omitempty"`        // 主机名 -> 日志记录器名称列表
+	SkipHosts         []string            `json:"skip_hosts,omitempty"`          // 不记录访问日志的主机
+	SkipUnmappedHosts bool                `json:"skip_unmapped_hosts,omitempty"` // 是否跳过未映射的主机
+	Extra             Extra               `json:"-"`                             // 未建模的字段 (如 should_log_credentials、trace)
+}
+
+// 日志配置 - 顶层 logging 应用
+type LoggingConfig struct {
+	Logs  map[string]*CustomLog `json:"logs,omitempty"` // 命名日志列表
+	Extra Extra                 `json:"-"`              // 未建模的字段 (如 sink)
+}
+
+// 自定义日志 - 定义日志的输出位置、格式和范围
+type CustomLog struct {
+	Writer  *LogWriter  `json:"writer,omitempty"`  // 日志输出
+	Encoder *LogEncoder `json:"encoder,omitempty"` // 日志编码格式
+	Level   string      `json:"level,omitempty"`   // 最低日志级别 (如 "INFO", "ERROR")
+	Include []string    `json:"include,omitempty"` // 包含的日志记录器名称
+	Exclude []string    `json:"exclude,omitempty"` // 排除的日志记录器名称
+	Extra   Extra       `json:"-"`                 // 未建模的字段 (如 sampling)
+}
+
+// 日志输出 - 支持 file、stdout、stderr、net
+type LogWriter struct {
+	Output string `json:"output"` // 输出类型
+
+	// file 输出字段
+	Filename     string `json:"filename,omitempty"`        // 日志文件路径
+	Roll         *bool  `json:"roll,omitempty"`            // 是否启用日志滚动 (默认启用)
+	RollSizeMB   int    `json:"roll_size_mb,omitempty"`    // 单个日志文件的最大大小 (MB)
+	RollKeep     int    `json:"roll_keep,omitempty"`       // 保留的日志文件数量
+	RollKeepDays int    `json:"roll_keep_days,omitempty"`  // 日志文件保留天数
+	RollLocal    bool   `json:"roll_local_time,omitempty"` // 滚动文件名是否使用本地时间
+
+	// net 输出字段
+	Address     string   `json:"address,omitempty"`      // 网络地址 (如 "tcp/logs.example.com:5140")
+	DialTimeout Duration `json:"dial_timeout,omitempty"` // 连接超时时间
+	SoftStart   bool     `json:"soft_start,omitempty"`   // 连接失败时是否继续启动
+
+	Extra Extra `json:"-"` // 未建模的字段
+}
+
+// 日志编码格式
+type LogEncoder struct {
+	Format string `json:"format"` // 编码格式 ("json" 或 "console")
+	Extra  Extra  `json:"-"`      // 未建模的字段 (如 time_format)
+}
+
+// HTTP 错误处理配置 - 处理器链返回错误时执行的路由
+type HTTPErrorConfig struct {
+	Routes []Route `json:"routes"` // 错误处理路由列表
+	Extra  Extra   `json:"-"`      // 未建模的字段
+}
+
+// 监听器包装器 - 定义在 TLS 之前/之后包装监听器的模块
+type ListenerWrapper struct {
+	Wrapper string   `json:"wrapper"`           // 包装器类型 (如 "proxy_protocol", "tls")
+	Timeout Duration `json:"timeout,omitempty"` // 读取 PROXY 头的超时时间
+	Allow   []string `json:"allow,omitempty"`   // 允许发送 PROXY 头的 CIDR 列表
+	Extra   Extra    `json:"-"`                 // 未建模的字段 (如 fallback_policy)
+}
+
+// 受信任代理 - 定义可信的上游代理来源
+type TrustedProxies struct {
+	Source string   `json:"source"`           // 来源模块 (如 "static")
+	Ranges []string `json:"ranges,omitempty"` // CIDR 范围列表
+	Extra  Extra    `json:"-"`                // 未建模的字段
 }
 
 // TLS 自动化策略 - 定义 TLS 证书自动化策略
 type TLSAutomationPolicy struct {
-	Issuers []TLSIssuer `json:"issuers"` // 证书颁发者列表
+	Subjects []string    `json:"subjects,omitempty"` // 策略适用的主体 (为空时适用于所有主体)
+	Issuers  []TLSIssuer `json:"issuers,omitempty"`  // 证书颁发者列表
+	Extra    Extra       `json:"-"`                  // 未建模的字段 (如 on_demand、key_type)
 }
 
 // TLS 证书颁发者 - 定义证书颁发者配置
 type TLSIssuer struct {
-	Module     string                 `json:"module"`               // 颁发者模块类型 (如 "acme", "internal")
-	Challenges map[string]interface{} `json:"challenges,omitempty"` // ACME 挑战配置
+	Module     string          `json:"module"`               // 颁发者模块类型 (如 "acme", "internal")
+	CA         string          `json:"ca,omitempty"`         // ACME 目录地址
+	Email      string          `json:"email,omitempty"`      // ACME 账户邮箱
+	Challenges *ACMEChallenges `json:"challenges,omitempty"` // ACME 挑战配置
+	Extra      Extra           `json:"-"`                    // 未建模的字段
 }
 
 // ACME DNS 提供商配置 - 定义 DNS 挑战提供商
 type ACMEProvider struct {
-	Name     string `json:"name"`     // 提供商名称 (如 "cloudflare")
-	APIToken string `json:"api_token"` // API 令牌
+	Name     string `json:"name"`                // 提供商名称 (如 "cloudflare")
+	APIToken string `json:"api_token,omitempty"` // API 令牌
+	Extra    Extra  `json:"-"`                   // 其他提供商的凭据字段
 }
 
 // PKI 配置 - 定义 PKI 证书颁发机构配置
 type PKIConfig struct {
-	InstallTrust bool `json:"install_trust"` // 是否安装信任根证书
+	Name                   string `json:"name,omitempty"`                     // CA 显示名称
+	RootCommonName         string `json:"root_common_name,omitempty"`         // 根证书通用名
+	IntermediateCommonName string `json:"intermediate_common_name,omitempty"` // 中间证书通用名
+	InstallTrust           *bool  `json:"install_trust,omitempty"`            // 是否安装信任根证书 (默认安装)
+	Extra                  Extra  `json:"-"`                                  // 未建模的字段
 }
\ No newline at end of file
@@ -0,0 +1,429 @@
+package types
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// KnownHandlers 已知的 HTTP 处理器模块 (Caddy 标准模块和 fastcaddy 使用的插件)
+// 其他处理器只产生警告 (可能来自插件)，向其中添加名称可以消除警告
+var KnownHandlers = map[string]bool{
+	"acme_server":           true,
+	"authentication":        true,
+	"copy_response":         true,
+	"copy_response_headers": true,
+	"encode":                true,
+	"error":                 true,
+	"file_server":           true,
+	"headers":               true,
+	"intercept":             true,
+	"invoke":                true,
+	"log_append":            true,
+	"map":                   true,
+	"metrics":               true,
+	"push":                  true,
+	"rate_limit":            true, // github.com/mholt/caddy-ratelimit
+	"request_body":          true,
+	"reverse_proxy":         true,
+	"rewrite":               true,
+	"static_response":       true,
+	"subroute":              true,
+	"templates":             true,
+	"tracing":               true,
+	"vars":                  true,
+}
+
+// ValidationError 配置校验错误
+type ValidationError struct {
+	Path    string // 出错位置 (相对于被校验的值，如 "routes/0/handle/1/upstreams/0/dial")
+	Message string // 错误说明
+	Warning bool   // 是否只是警告 (如未知的处理器)，警告不阻止写入
+}
+
+// Error 格式化校验错误
+func (e ValidationError) Error() string {
+	if e.Path == "" {
+		return e.Message
+	}
+	return fmt.Sprintf("%s: %s", e.Path, e.Message)
+}
+
+// ValidationErrors 多个配置校验错误
+type ValidationErrors []ValidationError
+
+// Error 将所有校验错误合并为一行
+func (e ValidationErrors) Error() string {
+	messages := make([]string, len(e))
+	for i, err := range e {
+		messages[i] = err.Error()
+	}
+	return strings.Join(messages, "; ")
+}
+
+// Errors 返回其中的错误 (不包括警告)
+func (e ValidationErrors) Errors() ValidationErrors {
+	return e.filter(false)
+}
+
+// Warnings 返回其中的警告
+func (e ValidationErrors) Warnings() ValidationErrors {
+	return e.filter(true)
+}
+
+// filter 按是否为警告筛选
+func (e ValidationErrors) filter(warning bool) ValidationErrors {
+	var result ValidationErrors
+	for _, err := range e {
+		if err.Warning == warning {
+			result = append(result, err)
+		}
+	}
+	return result
+}
+
+// hasPlaceholder 检查值是否包含占位符 (运行时才能确定，跳过格式校验)
+func hasPlaceholder(s string) bool {
+	return strings.Contains(s, "{") && strings.Contains(s, "}")
+}
+
+// ValidateHostname 验证主机名格式
+// 支持域名、IP 地址和通配符标签 (如 "*.example.com")，包含占位符时不校验
+func ValidateHostname(host string) error {
+	if host == "" {
+		return fmt.Errorf("主机名不能为空")
+	}
+	if hasPlaceholder(host) {
+		return nil
+	}
+	if ip := strings.TrimSuffix(strings.TrimPrefix(host, "["), "]"); net.ParseIP(ip) != nil {
+		return nil
+	}
+
+	name := strings.TrimSuffix(host, ".")
+	if len(name) > 253 {
+		return fmt.Errorf("主机名过长: %s", host)
+	}
+	for _, label := range strings.Split(name, ".") {
+		if label == "*" {
+			continue
+		}
+		if label == "" || len(label) > 63 {
+			return fmt.Errorf("无效的主机名: %s", host)
+		}
+		if strings.HasPrefix(label, "-") || strings.HasSuffix(label, "-") {
+			return fmt.Errorf("无效的主机名: %s (标签不能以 '-' 开头或结尾)", host)
+		}
+		for _, c := range label {
+			if !(c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '-' || c == '_' || c == '*') {
+				return fmt.Errorf("无效的主机名: %s (包含非法字符 %q)", host, c)
+			}
+		}
+	}
+	return nil
+}
+
+// ValidatePort 验证端口号 (1-65535)
+func ValidatePort(port string) error {
+	if hasPlaceholder(port) {
+		return nil
+	}
+	n, err := strconv.Atoi(port)
+	if err != nil || n < 1 || n > 65535 {
+		return fmt.Errorf("无效的端口: %s (范围 1-65535)", port)
+	}
+	return nil
+}
+
+// splitNetwork 拆分网络地址的网络类型前缀 (如 "tcp/", "unix/")
+func splitNetwork(addr string) (string, string) {
+	if i := strings.Index(addr, "/"); i > 0 {
+		switch network := addr[:i]; network {
+		case "tcp", "tcp4", "tcp6", "udp", "udp4", "udp6", "unix", "unixgram", "unixpacket":
+			return network, addr[i+1:]
+		}
+	}
+	return "", addr
+}
+
+// ValidateDial 验证上游地址 (如 "localhost:8080"、"unix//run/app.sock")
+func ValidateDial(dial string) error {
+	if dial == "" {
+		return fmt.Errorf("上游地址不能为空")
+	}
+	if hasPlaceholder(dial) {
+		return nil
+	}
+
+	network, addr := splitNetwork(dial)
+	if strings.HasPrefix(network, "unix") {
+		if addr == "" {
+			return fmt.Errorf("无效的上游地址: %s (缺少套接字路径)", dial)
+		}
+		return nil
+	}
+
+	host, port, err := net.SplitHostPort(addr)
+	if err != nil {
+		return fmt.Errorf("无效的上游地址: %s (格式应为 主机:端口)", dial)
+	}
+	if host != "" {
+		if err := ValidateHostname(host); err != nil {
+			return fmt.Errorf("无效的上游地址: %s: %w", dial, err)
+		}
+	}
+	if err := ValidatePort(port); err != nil {
+		return fmt.Errorf("无效的上游地址: %s: %w", dial, err)
+	}
+	return nil
+}
+
+// ValidateListen 验证监听地址 (如 ":443"、"127.0.0.1:8080"、":8000-8010")
+func ValidateListen(listen string) error {
+	if listen == "" {
+		return fmt.Errorf("监听地址不能为空")
+	}
+	if hasPlaceholder(listen) {
+		return nil
+	}
+
+	network, addr := splitNetwork(listen)
+	if strings.HasPrefix(network, "unix") {
+		return nil
+	}
+
+	host, port, err := net.SplitHostPort(addr)
+	if err != nil {
+		return fmt.Errorf("无效的监听地址: %s (格式应为 [主机]:端口)", listen)
+	}
+	if host != "" {
+		if err := ValidateHostname(host); err != nil {
+			return fmt.Errorf("无效的监听地址: %s: %w", listen, err)
+		}
+	}
+
+	// 端口范围 (如 8000-8010)
+	start, end := port, port
+	if i := strings.Index(port, "-"); i > 0 {
+		start, end = port[:i], port[i+1:]
+	}
+	for _, p := range []string{start, end} {
+		if err := ValidatePort(p); err != nil {
+			return fmt.Errorf("无效的监听地址: %s: %w", listen, err)
+		}
+	}
+	if s, _ := strconv.Atoi(start); s > 0 {
+		if e, _ := strconv.Atoi(end); e < s {
+			return fmt.Errorf("无效的监听地址: %s (端口范围起始值大于结束值)", listen)
+		}
+	}
+	return nil
+}
+
+// validator 配置结构校验器
+type validator struct {
+	ids  map[string]string // @id -> 首次出现的位置
+	errs ValidationErrors
+}
+
+// Validate 在本地校验配置的结构正确性，只返回错误 (忽略警告)
+// v 可以是完整配置，也可以是路由、处理器等部分配置；通过 JSON 结构识别各部分，
+// 检查 @id 唯一、主机名格式、上游和监听地址、端口范围、处理器名称和反向代理上游非空
+func Validate(v interface{}) error {
+	issues, err := Check(v)
+	if err != nil {
+		return err
+	}
+	if errs := issues.Errors(); len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+// Check 与 Validate 相同，但返回所有错误和警告
+// 未知的处理器和包含 '/' 的 @id (Caddy 可以加载，但无法通过 /id/ 访问) 作为警告返回
+func Check(v interface{}) (ValidationErrors, error) {
+	value, err := decode(v)
+	if err != nil {
+		return nil, err
+	}
+	val := &validator{ids: make(map[string]string)}
+	val.walk("", value)
+	return val.errs, nil
+}
+
+// CollectIDs 返回配置中出现的所有 @id (按出现顺序，可能重复)
+func CollectIDs(v interface{}) ([]string, error) {
+	value, err := decode(v)
+	if err != nil {
+		return nil, err
+	}
+	var ids []string
+	var walk func(value interface{})
+	walk = func(value interface{}) {
+		switch val := value.(type) {
+		case map[string]interface{}:
+			if id, ok := val["@id"].(string); ok && id != "" {
+				ids = append(ids, id)
+			}
+			for _, child := range val {
+				walk(child)
+			}
+		case []interface{}:
+			for _, child := range val {
+				walk(child)
+			}
+		}
+	}
+	walk(value)
+	return ids, nil
+}
+
+// decode 通过 JSON 序列化将值转换为 JSON 解码的表示
+func decode(v interface{}) (interface{}, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, fmt.Errorf("序列化配置失败: %w", err)
+	}
+	var value interface{}
+	if err := json.Unmarshal(data, &value); err != nil {
+		return nil, fmt.Errorf("解析配置失败: %w", err)
+	}
+	return value, nil
+}
+
+// add 记录校验错误
+func (v *validator) add(path, format string, args ...interface{}) {
+	v.errs = append(v.errs, ValidationError{Path: path, Message: fmt.Sprintf(format, args...)})
+}
+
+// warn 记录校验警告
+func (v *validator) warn(path, format string, args ...interface{}) {
+	v.errs = append(v.errs, ValidationError{Path: path, Message: fmt.Sprintf(format, args...), Warning: true})
+}
+
+// join 连接配置路径
+func join(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "/" + key
+}
+
+// walk 递归校验配置
+func (v *validator) walk(path string, value interface{}) {
+	switch val := value.(type) {
+	case map[string]interface{}:
+		v.object(path, val)
+		keys := make([]string, 0, len(val))
+		for key := range val {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			if key == "match" || key == "not" {
+				v.matchSets(join(path, key), val[key])
+			}
+			v.walk(join(path, key), val[key])
+		}
+	case []interface{}:
+		for i, child := range val {
+			v.walk(join(path, strconv.Itoa(i)), child)
+		}
+	}
+}
+
+// object 校验单个对象的 @id、处理器和监听地址
+func (v *validator) object(path string, obj map[string]interface{}) {
+	if raw, ok := obj["@id"]; ok {
+		id, isString := raw.(string)
+		switch {
+		case !isString || id == "":
+			v.add(join(path, "@id"), "@id 必须是非空字符串")
+		case strings.Contains(id, "/"):
+			v.warn(join(path, "@id"), "@id 包含 '/'，无法通过 /id/ 访问: %s", id)
+		default:
+			if first, dup := v.ids[id]; dup {
+				v.add(join(path, "@id"), "@id 重复: %s (首次出现于 %s)", id, first)
+			} else {
+				v.ids[id] = join(path, "@id")
+			}
+		}
+	}
+
+	if raw, ok := obj["handler"]; ok {
+		name, _ := raw.(string)
+		v.handler(path, name, obj)
+	}
+
+	if listen, ok := obj["listen"].([]interface{}); ok {
+		if len(listen) == 0 {
+			v.add(join(path, "listen"), "至少需要一个监听地址")
+		}
+		for i, raw := range listen {
+			addr, _ := raw.(string)
+			if err := ValidateListen(addr); err != nil {
+				v.add(join(path, fmt.Sprintf("listen/%d", i)), "%v", err)
+			}
+		}
+	}
+}
+
+// handler 校验处理器名称和必要字段
+func (v *validator) handler(path, name string, obj map[string]interface{}) {
+	if name == "" {
+		v.add(join(path, "handler"), "处理器名称不能为空")
+		return
+	}
+	if !KnownHandlers[name] {
+		v.warn(join(path, "handler"), "未知的处理器: %q (如果来自插件可以忽略)", name)
+		return
+	}
+
+	switch name {
+	case "reverse_proxy":
+		upstreams, _ := obj["upstreams"].([]interface{})
+		if _, dynamic := obj["dynamic_upstreams"]; len(upstreams) == 0 && !dynamic {
+			v.add(join(path, "upstreams"), "反向代理至少需要一个上游地址")
+		}
+		for i, raw := range upstreams {
+			upstream, _ := raw.(map[string]interface{})
+			dial, _ := upstream["dial"].(string)
+			if err := ValidateDial(dial); err != nil {
+				v.add(join(path, fmt.Sprintf("upstreams/%d/dial", i)), "%v", err)
+			}
+		}
+	case "static_response":
+		var code WeakString
+		switch raw := obj["status_code"].(type) {
+		case float64:
+			code = WeakString(strconv.FormatFloat(raw, 'f', -1, 64))
+		case string:
+			code = WeakString(raw)
+		}
+		if code == "" || hasPlaceholder(string(code)) {
+			return
+		}
+		if n, err := strconv.Atoi(string(code)); err != nil || n < 100 || n > 999 {
+			v.add(join(path, "status_code"), "无效的状态码: %s", code)
+		}
+	}
+}
+
+// matchSets 校验匹配集中的主机名
+func (v *validator) matchSets(path string, value interface{}) {
+	sets, _ := value.([]interface{})
+	for i, raw := range sets {
+		set, _ := raw.(map[string]interface{})
+		hosts, _ := set["host"].([]interface{})
+		for j, rawHost := range hosts {
+			host, _ := rawHost.(string)
+			if err := ValidateHostname(host); err != nil {
+				v.add(fmt.Sprintf("%s/%d/host/%d", path, i, j), "%v", err)
+			}
+		}
+	}
+}
@@ -0,0 +1,86 @@
+package fastcaddy
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/youfun/fastcaddy/internal/api"
+)
+
+// ErrTxDone 事务已经提交或回滚
+var ErrTxDone = errors.New("事务已经提交或回滚")
+
+// Tx 配置事务 - 在正在运行的配置的内存副本上暂存修改，提交时通过一次 /load 整体替换
+// Tx 内嵌使用暂存配置的 FastCaddy，所有便利方法和管理器都可以在事务中使用；
+// 任一步骤失败时回滚即可，正在运行的配置不会被修改
+type Tx struct {
+	*FastCaddy
+	parent *FastCaddy
+	stage  *api.Stage
+	etag   string // 开始事务时正在运行的配置的 Etag
+	done   bool
+}
+
+// Begin 开始配置事务
+func (fc *FastCaddy) Begin() (*Tx, error) {
+	var config interface{}
+	etag, err := fc.API.GetConfigWithEtag("/", &config)
+	if err != nil {
+		return nil, fmt.Errorf("获取配置失败: %w", err)
+	}
+
+	stage, err := api.NewStage(config, fc.API.HTTPClient.Transport)
+	if err != nil {
+		return nil, err
+	}
+	return &Tx{
+		FastCaddy: NewWithClient(fc.API.WithStage(stage)),
+		parent:    fc,
+		stage:     stage,
+		etag:      etag,
+	}, nil
+}
+
+// Config 返回事务中暂存的完整配置 (用于提交前预览)
+func (tx *Tx) Config() (json.RawMessage, error) {
+	return tx.stage.Config()
+}
+
+// Commit 通过 /load 一次性提交暂存的配置
+// 每次暂存写入时已校验写入的部分，提交时不再整体校验 (正在运行的配置中的插件处理器等不影响提交)；
+// 提交时带上 Begin 时的 Etag，期间正在运行的配置如被其他客户端修改，提交失败并返回 api.ErrConfigChanged
+func (tx *Tx) Commit() error {
+	if tx.done {
+		return ErrTxDone
+	}
+	config, err := tx.stage.Config()
+	if err != nil {
+		return err
+	}
+	client := *tx.parent.API
+	client.SkipValidation = true
+	if err := client.LoadIfMatch(config, tx.etag); err != nil {
+		return fmt.Errorf("提交事务失败: %w", err)
+	}
+	tx.done = true
+	return nil
+}
+
+// Rollback 放弃暂存的修改，提交后调用不做任何操作
+func (tx *Tx) Rollback() {
+	tx.done = true
+}
+
+// Transaction 在事务中执行 fn，fn 返回错误时回滚，否则提交 - 便利方法
+func (fc *FastCaddy) Transaction(fn func(tx *Tx) error) error {
+	tx, err := fc.Begin()
+	if err != nil {
+		return err
+	}
+	if err := fn(tx); err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}